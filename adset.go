@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+	"strconv"
+	"time"
+
+	"LegacyRoot/board"
+	"LegacyRoot/draft"
+	"LegacyRoot/matchpb"
+
+	"google.golang.org/protobuf/proto"
+)
+
+type SetupMode int32
+
+const (
+	StandardSetup SetupMode = iota
+	// AdsetSetup follows the tournament advanced setup rules.
+	AdsetSetup
+)
+
+// MapLandmarks are the landmarks a map's own rules put on it.
+var MapLandmarks = map[matchpb.MapType][]int32{
+	matchpb.MapType_LAKE:     {Ferry},
+	matchpb.MapType_MOUNTAIN: {Tower},
+}
+
+// ADSET is played by at least this many players.
+const minAdsetPlayers = 2
+
+// checkAdsetBots rejects ADSET configs asking for bots, which ADSET matches
+// don't have. Only configs whose bots were asked for explicitly are checked,
+// the default single bot being left out of ADSET matches.
+func checkAdsetBots(cfg *MatchCfg) error {
+	if cfg.Setup == AdsetSetup && cfg.BotEnemies > 0 {
+		return fmt.Errorf("ADSET matches have no bots, %d asked for", cfg.BotEnemies)
+	}
+	return nil
+}
+
+// generateAdsetMatch sets up a match the way tournaments do. The map is
+// picked at random, with its clearing suits shuffled unless it is Autumn, and
+// the players draft their factions from a hand holding at least one militant
+// faction, picking at random here. There are no bots or hirelings, and
+// landmarks are only those of the map's own rules. The deck is picked like
+// any other match's, without looking at earlier ones. Players sit in the
+// order they drafted for, seat 1 going first.
+func generateAdsetMatch(rng *rand.Rand, cfg *MatchCfg) (*matchpb.Match, error) {
+	match := &matchpb.Match{}
+	players, err := draftAdsetPlayers(rng, int(cfg.Players))
+	if err != nil {
+		return nil, err
+	}
+	match.Players = players
+	setAdsetMap(rng, match)
+	match.Deck = pickDeck(rng, &matchpb.Match{}, cfg)
+	seatInOrder(match, cfg.SetupOrder)
+	match.Homes = board.Homes(match)
+	return match, nil
+}
+
+// draftAdsetPlayers runs a draft for the seats, every seat picking at random
+// from what it is allowed to.
+func draftAdsetPlayers(rng *rand.Rand, seats int) ([]*matchpb.Faction, error) {
+	if seats < minAdsetPlayers {
+		return nil, fmt.Errorf("ADSET needs at least %d players, got %d", minAdsetPlayers, seats)
+	}
+	pool := []matchpb.FactionType{}
+	for _, f := range sortedKeys(FactionNames) {
+		pool = append(pool, matchpb.FactionType(f))
+	}
+	militant := factionsOfClass(Militant)
+	hand, err := draft.DealRequiring(rng, seats, pool, nil, militant)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for i := range seats {
+		names = append(names, strconv.Itoa(i+1))
+	}
+	d, err := draft.New(names, hand, 0, time.Time{})
+	if err != nil {
+		return nil, err
+	}
+	d.Require(militant)
+	for !d.Done() {
+		allowed := d.Allowed()
+		if err := d.Pick(d.Turn(), allowed[rng.Intn(len(allowed))], time.Time{}); err != nil {
+			return nil, err
+		}
+	}
+	factions, err := d.Result()
+	if err != nil {
+		return nil, err
+	}
+	players := []*matchpb.Faction{}
+	for _, f := range factions {
+		players = append(players, NewFaction(int32(f)))
+	}
+	return players, nil
+}
+
+// setAdsetMap picks the match's map along with its clearing suits and
+// landmarks.
+func setAdsetMap(rng *rand.Rand, match *matchpb.Match) {
+	m := sortedKeys(MapNames)[rng.Intn(len(MapNames))]
+	match.Map = &matchpb.MapVal{Type: matchpb.MapType(m), Name: MapNames[m]}
+	match.Clearings = adsetClearings(rng, match.Map.GetType())
+	match.Landmarks = adsetLandmarks(match.Map.GetType())
+}
+
+// adsetClearings returns the suits of the map's clearings, Autumn keeping its
+// printed ones.
+func adsetClearings(rng *rand.Rand, m matchpb.MapType) []*matchpb.Clearing {
+	layout := slices.Clone(board.Layouts[m].Clearings)
+	slices.SortFunc(layout, func(a, b board.Clearing) int { return int(a.Number - b.Number) })
+	suits := []matchpb.Suit{}
+	for _, c := range layout {
+		suits = append(suits, c.Suit)
+	}
+	if m != matchpb.MapType_AUTUMN {
+		rng.Shuffle(len(suits), func(i, j int) { suits[i], suits[j] = suits[j], suits[i] })
+	}
+	clearings := []*matchpb.Clearing{}
+	for i, c := range layout {
+		clearings = append(clearings, &matchpb.Clearing{Number: c.Number, Suit: suits[i]})
+	}
+	return clearings
+}
+
+func adsetLandmarks(m matchpb.MapType) []*matchpb.Landmark {
+	landmarks := []*matchpb.Landmark{}
+	for _, l := range MapLandmarks[m] {
+		landmarks = append(landmarks, &matchpb.Landmark{Type: matchpb.LandmarkType(l), Name: getLandmarkName(l)})
+	}
+	return landmarks
+}
+
+// rerollAdsetComponent is rerollComponent for ADSET matches. Rerolling the
+// map also sets up its clearings and landmarks again.
+func rerollAdsetComponent(rng *rand.Rand, match *matchpb.Match, component matchpb.Component, cfg *MatchCfg) *matchpb.Match {
+	rerolled := proto.Clone(match).(*matchpb.Match)
+	switch component {
+	case matchpb.Component_PLAYERS:
+		// A match that was drafted before has enough seats to draft again.
+		if players, err := draftAdsetPlayers(rng, len(match.GetPlayers())); err == nil {
+			rerolled.Players = players
+			seatInOrder(rerolled, cfg.SetupOrder)
+		}
+	case matchpb.Component_BOTS:
+		rerolled.Bots = nil
+	case matchpb.Component_HIRELINGS:
+		rerolled.Hirelings = nil
+	case matchpb.Component_MAP:
+		setAdsetMap(rng, rerolled)
+	case matchpb.Component_LANDMARKS:
+		rerolled.Landmarks = adsetLandmarks(match.GetMap().GetType())
+	case matchpb.Component_DECK:
+		rerolled.Deck = pickDeck(rng, &matchpb.Match{}, cfg)
+	case matchpb.Component_SEATS:
+		// Seats come out of the draft, only the setup order can change.
+		setSetupOrder(rerolled, cfg.SetupOrder)
+	}
+	rerolled.Homes = board.Homes(rerolled)
+	return rerolled
+}
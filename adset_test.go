@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"math/rand"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"LegacyRoot/matchpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func adsetCfg(players int32) *MatchCfg {
+	cfg := defaultMatchCfg()
+	cfg.Setup = AdsetSetup
+	cfg.Players = players
+	return &cfg
+}
+
+func hasMilitant(players []*matchpb.Faction) bool {
+	return slices.ContainsFunc(players, func(f *matchpb.Faction) bool {
+		return FactionClasses[int32(f.GetType())] == Militant
+	})
+}
+
+func TestFactionClasses(t *testing.T) {
+	for f := range FactionNames {
+		assert.Contains(t, FactionClasses, f, getFactionName(f))
+	}
+	assert.Equal(t, []matchpb.FactionType{
+		matchpb.FactionType_ALLIANCE,
+		matchpb.FactionType_VAGABOND,
+		matchpb.FactionType_RIVERFOLK,
+		matchpb.FactionType_LIZARD,
+		matchpb.FactionType_CORVID,
+	}, factionsOfClass(Insurgent))
+}
+
+func TestGenerateAdsetMatch(t *testing.T) {
+	prev := &matchpb.Match{}
+	for players := int32(2); players <= 4; players++ {
+		for seed := range int64(50) {
+			match, _, err := generateMatch(prev, adsetCfg(players), seed)
+			require.NoError(t, err)
+			assert.Len(t, match.GetPlayers(), int(players))
+			assert.True(t, hasMilitant(match.GetPlayers()), "%v", match.GetPlayers())
+			assert.Empty(t, match.GetBots())
+			assert.Empty(t, match.GetHirelings())
+			assert.Len(t, match.GetClearings(), clearingsPerMap)
+
+			switch match.GetMap().GetType() {
+			case matchpb.MapType_LAKE:
+				assert.Equal(t, []*matchpb.Landmark{{Type: matchpb.LandmarkType_FERRY, Name: "The Ferry"}}, match.GetLandmarks())
+			case matchpb.MapType_MOUNTAIN:
+				assert.Equal(t, []*matchpb.Landmark{{Type: matchpb.LandmarkType_TOWER, Name: "The Tower"}}, match.GetLandmarks())
+			default:
+				assert.Empty(t, match.GetLandmarks())
+			}
+		}
+	}
+
+	a, _, err := generateMatch(prev, adsetCfg(3), 7)
+	require.NoError(t, err)
+	b, _, err := generateMatch(prev, adsetCfg(3), 7)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(a, b))
+
+	// The default single player is raised to two in the config used, which
+	// is stored with the match, leaving the one asked for as it was. Bots and
+	// hirelings are left out of it.
+	cfg := adsetCfg(1)
+	cfg.BotEnemies, cfg.UseHirelings = 2, true
+	match, used, err := generateMatch(prev, cfg, 7)
+	require.NoError(t, err)
+	assert.Len(t, match.GetPlayers(), minAdsetPlayers)
+	assert.Equal(t, int32(minAdsetPlayers), used.Players)
+	assert.Zero(t, used.BotEnemies)
+	assert.False(t, used.UseHirelings)
+	assert.Equal(t, int32(1), cfg.Players)
+	assert.Equal(t, int32(2), cfg.BotEnemies)
+}
+
+func TestAdsetClearings(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	autumn := adsetClearings(rng, matchpb.MapType_AUTUMN)
+	assert.Equal(t, autumn, adsetClearings(rng, matchpb.MapType_AUTUMN))
+
+	// The other maps get their suits shuffled.
+	shuffled := false
+	first := adsetClearings(rng, matchpb.MapType_WINTER)
+	for range 10 {
+		clearings := adsetClearings(rng, matchpb.MapType_WINTER)
+		assert.Empty(t, Validate(&matchpb.Match{Clearings: clearings}))
+		if !proto.Equal(&matchpb.Match{Clearings: first}, &matchpb.Match{Clearings: clearings}) {
+			shuffled = true
+		}
+	}
+	assert.True(t, shuffled)
+}
+
+func TestRerollAdsetComponent(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	cfg := adsetCfg(3)
+	match, err := generateAdsetMatch(rng, cfg)
+	require.NoError(t, err)
+
+	for range 20 {
+		rerolled, err := rerollComponent(rng, match, matchpb.Component_MAP, cfg)
+		require.NoError(t, err)
+		assert.Len(t, rerolled.GetClearings(), clearingsPerMap)
+		assert.Empty(t, Validate(rerolled))
+		assert.True(t, proto.Equal(&matchpb.Match{Players: match.GetPlayers()}, &matchpb.Match{Players: rerolled.GetPlayers()}))
+
+		rerolled, err = rerollComponent(rng, match, matchpb.Component_PLAYERS, cfg)
+		require.NoError(t, err)
+		assert.Len(t, rerolled.GetPlayers(), 3)
+		assert.True(t, hasMilitant(rerolled.GetPlayers()))
+		assert.Equal(t, match.GetMap().GetType(), rerolled.GetMap().GetType())
+	}
+}
+
+func TestValidateClearings(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	clearings := adsetClearings(rng, matchpb.MapType_LAKE)
+	clearings[0].Suit = matchpb.Suit_BIRD
+	clearings[1].Number = clearings[2].Number
+	assert.Equal(t, []string{RuleClearingSuits, RuleClearingSuits}, rules(Validate(&matchpb.Match{Clearings: clearings})))
+}
+
+func TestSessionAdsetDraft(t *testing.T) {
+	ctx := context.Background()
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.pb"))
+	require.NoError(t, err)
+	session := newSessions(history, newFakeClock()).Create(defaultVoteRules())
+	cfg := adsetCfg(2)
+	record, err := generateStored(history, cfg)
+	require.NoError(t, err)
+	require.NoError(t, session.Publish(ctx, record, cfg))
+
+	for range 20 {
+		require.NoError(t, session.StartDraft(ctx, []string{"ana", "bo", "cy"}, time.Minute))
+		hand := session.Draft().Hand
+		assert.True(t, slices.ContainsFunc(hand, func(f string) bool {
+			return FactionClasses[int32(faction(f))] == Militant
+		}), "%v", hand)
+	}
+}
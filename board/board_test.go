@@ -0,0 +1,137 @@
+package board
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"LegacyRoot/matchpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+var mapNames = map[matchpb.MapType]string{
+	matchpb.MapType_AUTUMN:   "Autumn",
+	matchpb.MapType_WINTER:   "Winter",
+	matchpb.MapType_LAKE:     "Lake",
+	matchpb.MapType_MOUNTAIN: "Mountain",
+}
+
+func testMatch(m matchpb.MapType) *matchpb.Match {
+	return &matchpb.Match{
+		Players: []*matchpb.Faction{
+			{Type: matchpb.FactionType_EYRIE, Name: "Eyrie Dynasties"},
+			{Type: matchpb.FactionType_ALLIANCE, Name: "Woodland Alliance"},
+		},
+		Bots: []*matchpb.Bot{
+			{Type: matchpb.FactionType_MARQUISE, Name: "Mechanical Marquise 2.0"},
+			{Type: matchpb.FactionType_LIZARD, Name: "Logical Lizards"},
+		},
+		Map: &matchpb.MapVal{Type: m, Name: mapNames[m]},
+		Landmarks: []*matchpb.Landmark{
+			{Type: matchpb.LandmarkType_TOWER, Name: "The Tower"},
+			{Type: matchpb.LandmarkType_FERRY, Name: "The Ferry"},
+		},
+	}
+}
+
+func TestSVGGolden(t *testing.T) {
+	for m := range Layouts {
+		t.Run(m.String(), func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, SVG(&buf, testMatch(m)))
+
+			golden := filepath.Join("testdata", strings.ToLower(m.String())+".svg")
+			if *update {
+				require.NoError(t, os.WriteFile(golden, buf.Bytes(), 0o644))
+			}
+			want, err := os.ReadFile(golden)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), buf.String())
+		})
+	}
+}
+
+func TestLayouts(t *testing.T) {
+	for m, layout := range Layouts {
+		require.Len(t, layout.Clearings, 12, m)
+		suits := map[matchpb.Suit]int{}
+		for i, c := range layout.Clearings {
+			assert.Equal(t, int32(i+1), c.Number, m)
+			suits[c.Suit]++
+		}
+		assert.Equal(t, map[matchpb.Suit]int{matchpb.Suit_FOX: 4, matchpb.Suit_MOUSE: 4, matchpb.Suit_RABBIT: 4}, suits, m)
+		for _, path := range layout.Paths {
+			assert.NotZero(t, layout.clearing(path[0]).Number, m)
+			assert.NotZero(t, layout.clearing(path[1]).Number, m)
+		}
+		assert.Len(t, layout.Landmarks, 6, m)
+	}
+}
+
+func TestStartingClearings(t *testing.T) {
+	match := testMatch(matchpb.MapType_AUTUMN)
+	assert.Equal(t, map[matchpb.FactionType]int32{
+		matchpb.FactionType_MARQUISE: 1,
+		matchpb.FactionType_EYRIE:    2,
+		matchpb.FactionType_LIZARD:   3,
+	}, StartingClearings(match))
+
+	// Homes stored with the match win.
+	match.Homes = []*matchpb.Home{{Faction: matchpb.FactionType_EYRIE, Clearing: 4}}
+	assert.Equal(t, map[matchpb.FactionType]int32{matchpb.FactionType_EYRIE: 4}, StartingClearings(match))
+}
+
+func TestHomesSetupOrder(t *testing.T) {
+	match := testMatch(matchpb.MapType_AUTUMN)
+	// The Lizards set up first and take corner 1, so the Marquise takes 2 and
+	// the Eyrie the one across from it.
+	match.Seats = []*matchpb.Seat{
+		{Faction: matchpb.FactionType_EYRIE, SetupOrder: 3},
+		{Faction: matchpb.FactionType_ALLIANCE, SetupOrder: 4},
+		{Faction: matchpb.FactionType_MARQUISE, Bot: true, SetupOrder: 2},
+		{Faction: matchpb.FactionType_LIZARD, Bot: true, SetupOrder: 1},
+	}
+	assert.Equal(t, []*matchpb.Home{
+		{Faction: matchpb.FactionType_LIZARD, Clearing: 1},
+		{Faction: matchpb.FactionType_MARQUISE, Clearing: 2},
+		{Faction: matchpb.FactionType_EYRIE, Clearing: 3},
+	}, Homes(match))
+}
+
+func TestHomesPastCorners(t *testing.T) {
+	match := &matchpb.Match{Map: &matchpb.MapVal{Type: matchpb.MapType_AUTUMN}}
+	for _, f := range []matchpb.FactionType{
+		matchpb.FactionType_MARQUISE,
+		matchpb.FactionType_EYRIE,
+		matchpb.FactionType_LIZARD,
+		matchpb.FactionType_UNDERGROUND,
+		matchpb.FactionType_HUNDREDS,
+		matchpb.FactionType_KEEPERS,
+	} {
+		match.Players = append(match.Players, &matchpb.Faction{Type: f})
+	}
+	homes := Homes(match)
+	require.Len(t, homes, 6)
+	// 9 is the only clearing that borders no corner. After that every
+	// clearing borders a home, and the Lizards, setting up last, take the
+	// first one free.
+	assert.Equal(t, int32(9), homes[4].GetClearing())
+	assert.Equal(t, int32(5), homes[5].GetClearing())
+}
+
+func TestSVGMatchSuits(t *testing.T) {
+	match := testMatch(matchpb.MapType_WINTER)
+	match.Clearings = []*matchpb.Clearing{{Number: 1, Suit: matchpb.Suit_RABBIT}}
+	var buf bytes.Buffer
+	require.NoError(t, SVG(&buf, match))
+	c := Layouts[matchpb.MapType_WINTER].clearing(1)
+	assert.Contains(t, buf.String(), fmt.Sprintf(`<circle cx="%d" cy="%d" r="%d" fill="%s"`, c.X, c.Y, clearingRadius, suitColors[matchpb.Suit_RABBIT]))
+}
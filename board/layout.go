@@ -0,0 +1,113 @@
+// Package board draws the map of a match with its setup.
+package board
+
+import "LegacyRoot/matchpb"
+
+const (
+	fox    = matchpb.Suit_FOX
+	mouse  = matchpb.Suit_MOUSE
+	rabbit = matchpb.Suit_RABBIT
+)
+
+type Clearing struct {
+	Number int32
+	X, Y   int
+	Suit   matchpb.Suit
+}
+
+// Layout is a schematic of a map. Clearings are numbered by setup priority,
+// 1 to 4 being the corners with 1 across from 2 and 3 across from 4.
+type Layout struct {
+	Clearings []Clearing
+	Paths     [][2]int32
+	// Landmarks maps each landmark to the clearing it is set up in.
+	Landmarks map[matchpb.LandmarkType]int32
+}
+
+var Layouts = map[matchpb.MapType]Layout{
+	matchpb.MapType_AUTUMN: {
+		Clearings: []Clearing{
+			{1, 120, 110, fox}, {2, 880, 650, mouse}, {3, 120, 650, rabbit}, {4, 880, 110, rabbit},
+			{5, 400, 100, mouse}, {6, 640, 220, fox}, {7, 340, 300, rabbit}, {8, 120, 380, mouse},
+			{9, 600, 420, fox}, {10, 880, 380, mouse}, {11, 400, 640, fox}, {12, 640, 620, rabbit},
+		},
+		Paths: [][2]int32{
+			{1, 5}, {1, 7}, {1, 8}, {5, 6}, {5, 7}, {6, 4}, {6, 9}, {4, 10}, {7, 8}, {7, 9},
+			{8, 3}, {8, 11}, {9, 10}, {9, 12}, {10, 2}, {3, 11}, {11, 12}, {12, 2},
+		},
+		Landmarks: map[matchpb.LandmarkType]int32{
+			matchpb.LandmarkType_TOWER:   9,
+			matchpb.LandmarkType_FERRY:   11,
+			matchpb.LandmarkType_CITY:    6,
+			matchpb.LandmarkType_FORGE:   12,
+			matchpb.LandmarkType_TREETOP: 7,
+			matchpb.LandmarkType_MARKET:  10,
+		},
+	},
+	matchpb.MapType_WINTER: {
+		Clearings: []Clearing{
+			{1, 110, 120, mouse}, {2, 890, 640, fox}, {3, 110, 640, fox}, {4, 890, 120, rabbit},
+			{5, 320, 110, rabbit}, {6, 680, 110, mouse}, {7, 260, 340, fox}, {8, 500, 300, rabbit},
+			{9, 740, 340, mouse}, {10, 330, 620, mouse}, {11, 670, 620, rabbit}, {12, 500, 470, fox},
+		},
+		Paths: [][2]int32{
+			{1, 5}, {1, 7}, {5, 6}, {5, 8}, {6, 4}, {6, 8}, {4, 9}, {7, 8}, {7, 3}, {8, 9},
+			{8, 12}, {9, 2}, {9, 11}, {3, 10}, {10, 12}, {12, 11}, {11, 2},
+		},
+		Landmarks: map[matchpb.LandmarkType]int32{
+			matchpb.LandmarkType_TOWER:   8,
+			matchpb.LandmarkType_FERRY:   10,
+			matchpb.LandmarkType_CITY:    12,
+			matchpb.LandmarkType_FORGE:   7,
+			matchpb.LandmarkType_TREETOP: 6,
+			matchpb.LandmarkType_MARKET:  9,
+		},
+	},
+	matchpb.MapType_LAKE: {
+		Clearings: []Clearing{
+			{1, 120, 110, rabbit}, {2, 880, 650, fox}, {3, 120, 650, mouse}, {4, 880, 110, fox},
+			{5, 500, 90, mouse}, {6, 300, 260, fox}, {7, 700, 260, rabbit}, {8, 120, 380, fox},
+			{9, 880, 380, mouse}, {10, 300, 500, rabbit}, {11, 700, 500, mouse}, {12, 500, 670, rabbit},
+		},
+		Paths: [][2]int32{
+			{1, 5}, {1, 6}, {1, 8}, {5, 4}, {5, 6}, {5, 7}, {4, 7}, {4, 9}, {6, 8}, {6, 10},
+			{7, 9}, {7, 11}, {8, 10}, {8, 3}, {9, 2}, {10, 3}, {10, 12}, {3, 12}, {12, 11}, {11, 2},
+		},
+		Landmarks: map[matchpb.LandmarkType]int32{
+			matchpb.LandmarkType_TOWER:   5,
+			matchpb.LandmarkType_FERRY:   10,
+			matchpb.LandmarkType_CITY:    11,
+			matchpb.LandmarkType_FORGE:   8,
+			matchpb.LandmarkType_TREETOP: 6,
+			matchpb.LandmarkType_MARKET:  7,
+		},
+	},
+	matchpb.MapType_MOUNTAIN: {
+		Clearings: []Clearing{
+			{1, 120, 120, rabbit}, {2, 880, 640, mouse}, {3, 120, 640, fox}, {4, 880, 120, mouse},
+			{5, 380, 100, fox}, {6, 620, 140, rabbit}, {7, 300, 320, mouse}, {8, 520, 330, fox},
+			{9, 760, 360, rabbit}, {10, 260, 560, rabbit}, {11, 520, 560, mouse}, {12, 720, 620, fox},
+		},
+		Paths: [][2]int32{
+			{1, 5}, {1, 7}, {5, 6}, {5, 8}, {6, 4}, {6, 8}, {6, 9}, {4, 9}, {7, 8}, {7, 10},
+			{8, 9}, {8, 11}, {9, 2}, {9, 12}, {3, 10}, {10, 11}, {11, 12}, {12, 2},
+		},
+		Landmarks: map[matchpb.LandmarkType]int32{
+			matchpb.LandmarkType_TOWER:   8,
+			matchpb.LandmarkType_FERRY:   11,
+			matchpb.LandmarkType_CITY:    9,
+			matchpb.LandmarkType_FORGE:   7,
+			matchpb.LandmarkType_TREETOP: 10,
+			matchpb.LandmarkType_MARKET:  6,
+		},
+	},
+}
+
+func (l Layout) clearing(number int32) Clearing {
+	for _, c := range l.Clearings {
+		if c.Number == number {
+			return c
+		}
+	}
+	return Clearing{}
+}
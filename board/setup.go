@@ -0,0 +1,153 @@
+package board
+
+import (
+	"cmp"
+	"slices"
+
+	"LegacyRoot/matchpb"
+)
+
+// Factions that start in a home clearing.
+var homeFactions = map[matchpb.FactionType]bool{
+	matchpb.FactionType_MARQUISE:    true,
+	matchpb.FactionType_EYRIE:       true,
+	matchpb.FactionType_LIZARD:      true,
+	matchpb.FactionType_UNDERGROUND: true,
+	matchpb.FactionType_HUNDREDS:    true,
+	matchpb.FactionType_KEEPERS:     true,
+}
+
+// SetupPriority is the setup letter printed on each faction's board, A
+// being 1. The official setup rules set factions up in letter order.
+var SetupPriority = map[matchpb.FactionType]int{
+	matchpb.FactionType_MARQUISE:    1,
+	matchpb.FactionType_HUNDREDS:    2,
+	matchpb.FactionType_KEEPERS:     3,
+	matchpb.FactionType_UNDERGROUND: 4,
+	matchpb.FactionType_EYRIE:       5,
+	matchpb.FactionType_VAGABOND:    6,
+	matchpb.FactionType_RIVERFOLK:   7,
+	matchpb.FactionType_ALLIANCE:    8,
+	matchpb.FactionType_CORVID:      9,
+	matchpb.FactionType_LIZARD:      10,
+}
+
+// CompareSetupPriority orders factions by their setup letter, factions
+// without one last.
+func CompareSetupPriority(a, b matchpb.FactionType) int {
+	priority := func(f matchpb.FactionType) int {
+		if p, ok := SetupPriority[f]; ok {
+			return p
+		}
+		return len(SetupPriority) + 1
+	}
+	return cmp.Compare(priority(a), priority(b))
+}
+
+// acrossCorner maps each corner clearing to the one diagonally across.
+var acrossCorner = map[int32]int32{1: 2, 2: 1, 3: 4, 4: 3}
+
+// Homes sets up the starting clearing of every faction in play that has one,
+// players and bots alike, in the order the match's seats set up. A faction
+// takes a corner that is nobody's home yet, the Eyrie the one across from the
+// Marquise when it can. Once the corners run out it takes a clearing that is
+// no home and not next to one. Ties go to the clearing first in setup
+// priority.
+func Homes(match *matchpb.Match) []*matchpb.Home {
+	layout, ok := Layouts[match.GetMap().GetType()]
+	if !ok {
+		return nil
+	}
+	homes := []*matchpb.Home{}
+	for _, faction := range FactionsInSetupOrder(match) {
+		if !homeFactions[faction] {
+			continue
+		}
+		if clearing := layout.home(faction, homes); clearing != 0 {
+			homes = append(homes, &matchpb.Home{Faction: faction, Clearing: clearing})
+		}
+	}
+	return homes
+}
+
+// StartingClearings returns each faction's home, the match's own when it has
+// them and set up by Homes for older matches.
+func StartingClearings(match *matchpb.Match) map[matchpb.FactionType]int32 {
+	homes := match.GetHomes()
+	if len(homes) == 0 {
+		homes = Homes(match)
+	}
+	clearings := map[matchpb.FactionType]int32{}
+	for _, h := range homes {
+		clearings[h.GetFaction()] = h.GetClearing()
+	}
+	return clearings
+}
+
+// FactionsInSetupOrder returns the factions at the table in the order they
+// set up, going by their setup letters when the match has no seats.
+func FactionsInSetupOrder(match *matchpb.Match) []matchpb.FactionType {
+	factions := []matchpb.FactionType{}
+	if seats := match.GetSeats(); len(seats) > 0 {
+		seats = slices.Clone(seats)
+		slices.SortStableFunc(seats, func(a, b *matchpb.Seat) int { return cmp.Compare(a.GetSetupOrder(), b.GetSetupOrder()) })
+		for _, seat := range seats {
+			factions = append(factions, seat.GetFaction())
+		}
+		return factions
+	}
+	for _, p := range match.GetPlayers() {
+		factions = append(factions, p.GetType())
+	}
+	for _, b := range match.GetBots() {
+		factions = append(factions, b.GetType())
+	}
+	slices.SortStableFunc(factions, CompareSetupPriority)
+	return factions
+}
+
+// home picks the clearing faction starts in, 0 when every clearing is taken.
+func (l Layout) home(faction matchpb.FactionType, homes []*matchpb.Home) int32 {
+	taken := map[int32]bool{}
+	for _, h := range homes {
+		taken[h.GetClearing()] = true
+	}
+	if faction == matchpb.FactionType_EYRIE {
+		for _, h := range homes {
+			if across, ok := acrossCorner[h.GetClearing()]; ok && h.GetFaction() == matchpb.FactionType_MARQUISE && !taken[across] {
+				return across
+			}
+		}
+	}
+
+	numbers := []int32{}
+	for _, c := range l.Clearings {
+		numbers = append(numbers, c.Number)
+	}
+	slices.Sort(numbers)
+	for _, n := range numbers {
+		if _, corner := acrossCorner[n]; corner && !taken[n] {
+			return n
+		}
+	}
+	nextToHome := map[int32]bool{}
+	for _, path := range l.Paths {
+		if taken[path[0]] {
+			nextToHome[path[1]] = true
+		}
+		if taken[path[1]] {
+			nextToHome[path[0]] = true
+		}
+	}
+	for _, n := range numbers {
+		if !taken[n] && !nextToHome[n] {
+			return n
+		}
+	}
+	for _, n := range numbers {
+		if !taken[n] {
+			return n
+		}
+	}
+	return 0
+}
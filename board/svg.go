@@ -0,0 +1,114 @@
+package board
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"io"
+
+	"LegacyRoot/matchpb"
+)
+
+const (
+	width          = 1000
+	height         = 800
+	clearingRadius = 42
+)
+
+var suitColors = map[matchpb.Suit]string{
+	matchpb.Suit_FOX:    "#d8472b",
+	matchpb.Suit_MOUSE:  "#e89a2c",
+	matchpb.Suit_RABBIT: "#f2d15c",
+}
+
+var FactionColors = map[matchpb.FactionType]string{
+	matchpb.FactionType_MARQUISE:    "#e27a21",
+	matchpb.FactionType_EYRIE:       "#3a6cb4",
+	matchpb.FactionType_ALLIANCE:    "#4a9c3d",
+	matchpb.FactionType_VAGABOND:    "#777777",
+	matchpb.FactionType_RIVERFOLK:   "#4bb3a4",
+	matchpb.FactionType_LIZARD:      "#e6c33a",
+	matchpb.FactionType_UNDERGROUND: "#b58b5a",
+	matchpb.FactionType_CORVID:      "#5b3c88",
+	matchpb.FactionType_HUNDREDS:    "#a62b2b",
+	matchpb.FactionType_KEEPERS:     "#7b8a8e",
+}
+
+// SVG draws the match's map with the suit of every clearing, where its
+// landmarks go and which clearing each faction starts in. Suits and homes set
+// up with the match replace the printed suits and the default homes.
+func SVG(w io.Writer, match *matchpb.Match) error {
+	layout, ok := Layouts[match.GetMap().GetType()]
+	if !ok {
+		return fmt.Errorf("no layout for map %v", match.GetMap().GetType())
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, `<svg xmlns="http://www.w3.org/2000/svg" viewBox="0 0 %d %d" width="%d" height="%d" font-family="sans-serif">`+"\n", width, height, width, height)
+	fmt.Fprintf(&buf, `<rect width="%d" height="%d" fill="#f4ecd8"/>`+"\n", width, height)
+	fmt.Fprintf(&buf, `<text x="20" y="%d" font-size="28" font-weight="bold">%s</text>`+"\n", height-16, html.EscapeString(match.GetMap().GetName()))
+
+	buf.WriteString(`<g stroke="#8a6d3b" stroke-width="10" stroke-linecap="round">` + "\n")
+	for _, path := range layout.Paths {
+		a, b := layout.clearing(path[0]), layout.clearing(path[1])
+		fmt.Fprintf(&buf, `<line x1="%d" y1="%d" x2="%d" y2="%d"/>`+"\n", a.X, a.Y, b.X, b.Y)
+	}
+	buf.WriteString("</g>\n")
+
+	suits := map[int32]matchpb.Suit{}
+	for _, c := range match.GetClearings() {
+		suits[c.GetNumber()] = c.GetSuit()
+	}
+	for _, c := range layout.Clearings {
+		if suit, ok := suits[c.Number]; ok {
+			c.Suit = suit
+		}
+		fmt.Fprintf(&buf, `<circle cx="%d" cy="%d" r="%d" fill="%s" stroke="#5a4632" stroke-width="4"/>`+"\n", c.X, c.Y, clearingRadius, suitColors[c.Suit])
+		fmt.Fprintf(&buf, `<text x="%d" y="%d" text-anchor="middle" font-size="24" font-weight="bold">%d</text>`+"\n", c.X, c.Y+2, c.Number)
+		fmt.Fprintf(&buf, `<text x="%d" y="%d" text-anchor="middle" font-size="14">%s</text>`+"\n", c.X, c.Y+22, suitName(c.Suit))
+	}
+
+	names := map[matchpb.FactionType]string{}
+	for _, p := range match.GetPlayers() {
+		names[p.GetType()] = p.GetName()
+	}
+	for _, b := range match.GetBots() {
+		names[b.GetType()] = b.GetName()
+	}
+	homes := match.GetHomes()
+	if len(homes) == 0 {
+		homes = Homes(match)
+	}
+	for _, home := range homes {
+		faction := home.GetFaction()
+		c := layout.clearing(home.GetClearing())
+		fmt.Fprintf(&buf, `<circle cx="%d" cy="%d" r="%d" fill="none" stroke="%s" stroke-width="8"/>`+"\n", c.X, c.Y, clearingRadius+10, FactionColors[faction])
+		fmt.Fprintf(&buf, `<text x="%d" y="%d" text-anchor="middle" font-size="18" fill="%s">%s</text>`+"\n", c.X, c.Y+clearingRadius+34, FactionColors[faction], html.EscapeString(names[faction]))
+	}
+
+	for _, landmark := range match.GetLandmarks() {
+		number, ok := layout.Landmarks[landmark.GetType()]
+		if !ok {
+			continue
+		}
+		c := layout.clearing(number)
+		fmt.Fprintf(&buf, `<rect x="%d" y="%d" width="24" height="24" fill="#3b2f2f" transform="rotate(45 %d %d)"/>`+"\n", c.X+26, c.Y-50, c.X+38, c.Y-38)
+		fmt.Fprintf(&buf, `<text x="%d" y="%d" text-anchor="middle" font-size="16">%s</text>`+"\n", c.X, c.Y-clearingRadius-14, html.EscapeString(landmark.GetName()))
+	}
+
+	buf.WriteString("</svg>\n")
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+func suitName(suit matchpb.Suit) string {
+	switch suit {
+	case matchpb.Suit_FOX:
+		return "fox"
+	case matchpb.Suit_MOUSE:
+		return "mouse"
+	case matchpb.Suit_RABBIT:
+		return "rabbit"
+	}
+	return ""
+}
@@ -0,0 +1,655 @@
+package main
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"LegacyRoot/matchpb"
+
+	"github.com/labstack/echo"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// defaultScoring gives 3, 2 and 1 points to the first three places.
+var defaultScoring = []int32{3, 2, 1}
+
+// Campaigns keeps every campaign, stored like the history as length
+// delimited binary messages, or protojson lines for a .jsonl path. An empty
+// path keeps them in memory only.
+type Campaigns struct {
+	mu        sync.Mutex
+	path      string
+	campaigns []*matchpb.Campaign
+}
+
+func openCampaigns(path string) (*Campaigns, error) {
+	if path == "" {
+		return &Campaigns{}, nil
+	}
+	campaigns, err := readStore(path, "campaigns", func() *matchpb.Campaign { return &matchpb.Campaign{} }, func(line []byte, campaign *matchpb.Campaign) error {
+		return protojson.Unmarshal(line, campaign)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &Campaigns{path: path, campaigns: campaigns}, nil
+}
+
+// write rewrites the whole file. c.mu must be held.
+func (c *Campaigns) write(campaigns []*matchpb.Campaign) error {
+	if c.path == "" {
+		return nil
+	}
+	return writeStore(c.path, "campaigns", campaigns)
+}
+
+// Add stores a new campaign under a new id.
+func (c *Campaigns) Add(campaign *matchpb.Campaign) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	taken := func(id string) bool {
+		return slices.ContainsFunc(c.campaigns, func(other *matchpb.Campaign) bool { return other.GetId() == id })
+	}
+	if campaign.Id == "" || taken(campaign.Id) {
+		campaign.Id = unusedMatchId(taken)
+	}
+	campaigns := append(slices.Clone(c.campaigns), campaign)
+	if err := c.write(campaigns); err != nil {
+		return err
+	}
+	c.campaigns = campaigns
+	return nil
+}
+
+// Modify changes a copy of the campaign with the given id and stores it
+// unless modify fails, returning the stored campaign. Campaigns are locked
+// for the whole change, so changes can't undo each other.
+func (c *Campaigns) Modify(id string, modify func(*matchpb.Campaign) error) (*matchpb.Campaign, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	i := slices.IndexFunc(c.campaigns, func(other *matchpb.Campaign) bool { return other.GetId() == id })
+	if i < 0 {
+		return nil, fmt.Errorf("no campaign with id %q", id)
+	}
+	campaign := proto.Clone(c.campaigns[i]).(*matchpb.Campaign)
+	if err := modify(campaign); err != nil {
+		return nil, err
+	}
+	campaigns := slices.Clone(c.campaigns)
+	campaigns[i] = campaign
+	if err := c.write(campaigns); err != nil {
+		return nil, err
+	}
+	c.campaigns = campaigns
+	return campaign, nil
+}
+
+func (c *Campaigns) Get(id string) (*matchpb.Campaign, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, campaign := range c.campaigns {
+		if campaign.GetId() == id {
+			return campaign, true
+		}
+	}
+	return nil, false
+}
+
+// List returns the stored campaigns, oldest first.
+func (c *Campaigns) List() []*matchpb.Campaign {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return slices.Clone(c.campaigns)
+}
+
+// newCampaign sets up a campaign with its first season scheduled.
+func newCampaign(rng *rand.Rand, name string, roster []string, scoring []int32, tableSize, rounds int32, everyFaction bool, cfg *MatchCfg) (*matchpb.Campaign, error) {
+	if len(roster) == 0 {
+		return nil, errors.New("the roster is empty")
+	}
+	seen := map[string]bool{}
+	for _, player := range roster {
+		if seen[player] {
+			return nil, fmt.Errorf("%s is on the roster twice", player)
+		}
+		seen[player] = true
+	}
+	if tableSize < 1 || int(tableSize) > len(roster) {
+		return nil, fmt.Errorf("table size %d doesn't fit a roster of %d", tableSize, len(roster))
+	}
+	if rounds < 1 {
+		return nil, fmt.Errorf("a season needs at least one round, got %d", rounds)
+	}
+	campaign := &matchpb.Campaign{
+		Name:            name,
+		CreatedAt:       timestamppb.Now(),
+		Roster:          roster,
+		Scoring:         scoring,
+		TableSize:       tableSize,
+		RoundsPerSeason: rounds,
+		EveryFaction:    everyFaction,
+		Config:          matchCfgToProto(cfg),
+	}
+	scheduleSeason(rng, campaign)
+	return campaign, nil
+}
+
+func currentSeason(campaign *matchpb.Campaign) int32 {
+	season := int32(0)
+	for _, game := range campaign.GetGames() {
+		season = max(season, game.GetSeason())
+	}
+	return season
+}
+
+// scheduleSeason adds the games of the next season. Every round seats as
+// many full tables as the roster fills, those who played least so far
+// sitting down first.
+func scheduleSeason(rng *rand.Rand, campaign *matchpb.Campaign) {
+	played := map[string]int{}
+	for _, game := range campaign.GetGames() {
+		for _, player := range game.GetPlayers() {
+			played[player]++
+		}
+	}
+	season := currentSeason(campaign) + 1
+	size := int(campaign.GetTableSize())
+	for round := range campaign.GetRoundsPerSeason() {
+		roster := slices.Clone(campaign.GetRoster())
+		rng.Shuffle(len(roster), func(i, j int) { roster[i], roster[j] = roster[j], roster[i] })
+		slices.SortStableFunc(roster, func(a, b string) int { return cmp.Compare(played[a], played[b]) })
+		for table := range len(roster) / size {
+			players := roster[table*size : (table+1)*size]
+			for _, player := range players {
+				played[player]++
+			}
+			campaign.Games = append(campaign.Games, &matchpb.CampaignGame{
+				Season:  season,
+				Round:   round + 1,
+				Players: slices.Clone(players),
+			})
+		}
+	}
+}
+
+// campaignFactions picks the faction each player of a game plays. With
+// EveryFaction players don't get a faction they played earlier in the season
+// until they played them all, as far as the table allows.
+func campaignFactions(rng *rand.Rand, campaign *matchpb.Campaign, game *matchpb.CampaignGame, history *History) []matchpb.FactionType {
+	played := map[string]map[matchpb.FactionType]bool{}
+	for _, player := range game.GetPlayers() {
+		played[player] = map[matchpb.FactionType]bool{}
+	}
+	if campaign.GetEveryFaction() {
+		for _, other := range campaign.GetGames() {
+			if other.GetSeason() != game.GetSeason() || other.GetMatchId() == "" {
+				continue
+			}
+			record, ok := history.Get(other.GetMatchId())
+			if !ok {
+				continue
+			}
+			for i, player := range other.GetPlayers() {
+				if i < len(record.GetMatch().GetPlayers()) && played[player] != nil {
+					played[player][record.GetMatch().GetPlayers()[i].GetType()] = true
+				}
+			}
+		}
+	}
+
+	all := []matchpb.FactionType{}
+	for _, f := range sortedKeys(FactionNames) {
+		all = append(all, matchpb.FactionType(f))
+	}
+	for _, player := range game.GetPlayers() {
+		// Starting over once every faction was played.
+		if len(played[player]) >= len(all) {
+			played[player] = map[matchpb.FactionType]bool{}
+		}
+	}
+
+	players := game.GetPlayers()
+	// Factions more of the table still needs go first, as leaving them for
+	// later is what runs a season out of factions to hand out.
+	needed := func(f matchpb.FactionType) int {
+		n := 0
+		for _, player := range players {
+			if !played[player][f] {
+				n++
+			}
+		}
+		return n
+	}
+	order := []int{}
+	for i := range players {
+		order = append(order, i)
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(len(played[players[b]]), len(played[players[a]]))
+	})
+
+	factions := make([]matchpb.FactionType, len(players))
+	taken := map[matchpb.FactionType]bool{}
+	var assign func(k int, fresh bool) bool
+	assign = func(k int, fresh bool) bool {
+		if k == len(order) {
+			return true
+		}
+		i := order[k]
+		options := []matchpb.FactionType{}
+		for _, f := range all {
+			if !taken[f] && (!fresh || !played[players[i]][f]) {
+				options = append(options, f)
+			}
+		}
+		rng.Shuffle(len(options), func(a, b int) { options[a], options[b] = options[b], options[a] })
+		slices.SortStableFunc(options, func(a, b matchpb.FactionType) int { return cmp.Compare(needed(b), needed(a)) })
+		for _, f := range options {
+			factions[i], taken[f] = f, true
+			if assign(k+1, fresh) {
+				return true
+			}
+			taken[f] = false
+		}
+		return false
+	}
+	// When the table can't all get a fresh faction, some play one again.
+	if !assign(0, true) {
+		assign(0, false)
+	}
+	return factions
+}
+
+// generateCampaignGame generates the match of a scheduled game from the
+// campaign's config, with the factions the campaign hands the players, and
+// stores it in the history. ADSET games draft their factions instead.
+func generateCampaignGame(history *History, campaign *matchpb.Campaign, game *matchpb.CampaignGame) (*matchpb.MatchRecord, error) {
+	cfg := matchCfgFromProto(campaign.GetConfig())
+	cfg.Players = int32(len(game.GetPlayers()))
+	seed := newSeed()
+	if cfg.Setup != AdsetSetup {
+		cfg.Factions = campaignFactions(rand.New(rand.NewSource(seed)), campaign, game, history)
+	}
+	coverHistory(history, cfg)
+	prev := history.Last()
+	if prev == nil {
+		prev = &matchpb.Match{}
+	}
+	match, used, err := generateMatch(prev, cfg, seed)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	}
+	return history.AddGenerated(match, seed, used)
+}
+
+// Standing is a player's place in a campaign.
+type Standing struct {
+	Player string `json:"player"`
+	Points int32  `json:"points"`
+	Games  int    `json:"games"`
+	Wins   int    `json:"wins"`
+}
+
+// standings adds up the points of every game with results, ranking results
+// by score with ties sharing the better rank.
+func standings(campaign *matchpb.Campaign, history *History) []Standing {
+	byPlayer := map[string]*Standing{}
+	for _, player := range campaign.GetRoster() {
+		byPlayer[player] = &Standing{Player: player}
+	}
+	for _, game := range campaign.GetGames() {
+		record, ok := history.Get(game.GetMatchId())
+		if !ok {
+			continue
+		}
+		for _, result := range record.GetResults() {
+			standing, ok := byPlayer[result.GetPlayer()]
+			if !ok {
+				continue
+			}
+			rank := 0
+			for _, other := range record.GetResults() {
+				if other.GetScore() > result.GetScore() {
+					rank++
+				}
+			}
+			if rank < len(campaign.GetScoring()) {
+				standing.Points += campaign.GetScoring()[rank]
+			}
+			standing.Games++
+			if result.GetWinner() {
+				standing.Wins++
+			}
+		}
+	}
+
+	table := []Standing{}
+	for _, player := range campaign.GetRoster() {
+		table = append(table, *byPlayer[player])
+	}
+	slices.SortStableFunc(table, func(a, b Standing) int {
+		if c := cmp.Compare(b.Points, a.Points); c != 0 {
+			return c
+		}
+		return cmp.Compare(b.Wins, a.Wins)
+	})
+	return table
+}
+
+// campaignGameParam reads the campaign and game index from the path, the
+// game being counted from 0 in schedule order.
+func campaignGameParam(c echo.Context, campaigns *Campaigns) (*matchpb.Campaign, int, error) {
+	campaign, err := campaignParam(c, campaigns)
+	if err != nil {
+		return nil, 0, err
+	}
+	i, err := strconv.Atoi(c.Param("game"))
+	if err != nil || i < 0 || i >= len(campaign.GetGames()) {
+		return nil, 0, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("no game %q", c.Param("game")))
+	}
+	return campaign, i, nil
+}
+
+func campaignParam(c echo.Context, campaigns *Campaigns) (*matchpb.Campaign, error) {
+	campaign, ok := campaigns.Get(c.Param("id"))
+	if !ok {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("no campaign with id %q", c.Param("id")))
+	}
+	return proto.Clone(campaign).(*matchpb.Campaign), nil
+}
+
+// formCampaign reads a new campaign from a form: name, roster (comma
+// separated), scoring (comma separated points, 3,2,1 by default),
+// table-size, rounds and every-faction, along with the match options.
+func formCampaign(c echo.Context) (*matchpb.Campaign, error) {
+	cfg, err := formCfg(c)
+	if err != nil {
+		return nil, err
+	}
+	roster := []string{}
+	for _, player := range strings.Split(c.FormValue("roster"), ",") {
+		if player = strings.TrimSpace(player); player != "" {
+			roster = append(roster, player)
+		}
+	}
+	scoring := defaultScoring
+	if s := c.FormValue("scoring"); s != "" {
+		scoring = []int32{}
+		for _, points := range strings.Split(s, ",") {
+			p, err := strconv.ParseInt(strings.TrimSpace(points), 10, 32)
+			if err != nil {
+				return nil, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid scoring: %v", err))
+			}
+			scoring = append(scoring, int32(p))
+		}
+	}
+	intValue := func(name string, def int) (int32, error) {
+		s := c.FormValue(name)
+		if s == "" {
+			return int32(def), nil
+		}
+		v, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return 0, echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid %s: %v", name, err))
+		}
+		return int32(v), nil
+	}
+	tableSize, err := intValue("table-size", min(len(roster), 4))
+	if err != nil {
+		return nil, err
+	}
+	rounds, err := intValue("rounds", len(FactionNames))
+	if err != nil {
+		return nil, err
+	}
+	rng := rand.New(rand.NewSource(newSeed()))
+	campaign, err := newCampaign(rng, c.FormValue("name"), roster, scoring, tableSize, rounds, c.FormValue("every-faction") == "true", cfg)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return campaign, nil
+}
+
+type campaignResponse struct {
+	Id        string         `json:"id"`
+	Name      string         `json:"name"`
+	Roster    []string       `json:"roster"`
+	Scoring   []int32        `json:"scoring"`
+	Games     []campaignGame `json:"games"`
+	Standings []Standing     `json:"standings"`
+}
+
+type campaignGame struct {
+	Season  int32    `json:"season"`
+	Round   int32    `json:"round"`
+	Players []string `json:"players"`
+	MatchId string   `json:"match_id,omitempty"`
+}
+
+func newCampaignResponse(campaign *matchpb.Campaign, history *History) campaignResponse {
+	response := campaignResponse{
+		Id:        campaign.GetId(),
+		Name:      campaign.GetName(),
+		Roster:    campaign.GetRoster(),
+		Scoring:   campaign.GetScoring(),
+		Games:     []campaignGame{},
+		Standings: standings(campaign, history),
+	}
+	for _, game := range campaign.GetGames() {
+		response.Games = append(response.Games, campaignGame{
+			Season:  game.GetSeason(),
+			Round:   game.GetRound(),
+			Players: game.GetPlayers(),
+			MatchId: game.GetMatchId(),
+		})
+	}
+	return response
+}
+
+// respondCampaign answers API requests with the campaign as JSON and
+// browsers with a redirect to its page.
+func respondCampaign(c echo.Context, code int, campaign *matchpb.Campaign, history *History) error {
+	if strings.HasPrefix(c.Path(), "/api/") {
+		return c.JSON(code, newCampaignResponse(campaign, history))
+	}
+	return c.Redirect(http.StatusSeeOther, "/campaigns/"+campaign.GetId())
+}
+
+func createCampaignHandler(campaigns *Campaigns, history *History) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		campaign, err := formCampaign(c)
+		if err != nil {
+			return err
+		}
+		if err := campaigns.Add(campaign); err != nil {
+			return err
+		}
+		return respondCampaign(c, http.StatusCreated, campaign, history)
+	}
+}
+
+func campaignHandler(campaigns *Campaigns, history *History) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		campaign, err := campaignParam(c, campaigns)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusOK, newCampaignResponse(campaign, history))
+	}
+}
+
+// scheduleSeasonHandler adds the next season's games to a campaign.
+func scheduleSeasonHandler(campaigns *Campaigns, history *History) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		campaign, err := campaignParam(c, campaigns)
+		if err != nil {
+			return err
+		}
+		campaign, err = campaigns.Modify(campaign.GetId(), func(campaign *matchpb.Campaign) error {
+			scheduleSeason(rand.New(rand.NewSource(newSeed())), campaign)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		return respondCampaign(c, http.StatusOK, campaign, history)
+	}
+}
+
+// generateGameHandler generates the match of a scheduled game. Games keep
+// their match once it's generated, and the match is taken out of the history
+// again when the campaign can't be stored with it.
+func generateGameHandler(campaigns *Campaigns, history *History) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		campaign, i, err := campaignGameParam(c, campaigns)
+		if err != nil {
+			return err
+		}
+		var record *matchpb.MatchRecord
+		campaign, err = campaigns.Modify(campaign.GetId(), func(campaign *matchpb.Campaign) error {
+			game := campaign.GetGames()[i]
+			if game.GetMatchId() != "" {
+				return echo.NewHTTPError(http.StatusConflict, "the game already has a match")
+			}
+			var err error
+			if record, err = generateCampaignGame(history, campaign, game); err != nil {
+				return err
+			}
+			game.MatchId = record.GetId()
+			return nil
+		})
+		if err != nil && record != nil {
+			return errors.Join(err, history.Remove(record.GetId()))
+		}
+		if err != nil {
+			return err
+		}
+		return respondCampaign(c, http.StatusOK, campaign, history)
+	}
+}
+
+// gameResultsHandler records the results of a game's match, given in
+// results as name:FACTION:score separated by spaces or new lines. The
+// highest score wins.
+func gameResultsHandler(campaigns *Campaigns, history *History) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		campaign, i, err := campaignGameParam(c, campaigns)
+		if err != nil {
+			return err
+		}
+		game := campaign.GetGames()[i]
+		record, ok := history.Get(game.GetMatchId())
+		if !ok {
+			return echo.NewHTTPError(http.StatusConflict, "the game has no match yet")
+		}
+		results := []*matchpb.Result{}
+		best := int32(0)
+		for _, s := range strings.Fields(c.FormValue("results")) {
+			result, err := parseResult(s)
+			if err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+			}
+			seat := slices.Index(game.GetPlayers(), result.GetPlayer())
+			if seat < 0 || seat >= len(record.GetMatch().GetPlayers()) {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s didn't play this game", result.GetPlayer()))
+			}
+			if faction := record.GetMatch().GetPlayers()[seat].GetType(); result.GetFaction() != faction {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("%s played %s in this game", result.GetPlayer(), factionParamName(faction)))
+			}
+			results = append(results, result)
+			best = max(best, result.GetScore())
+		}
+		if len(results) == 0 {
+			return echo.NewHTTPError(http.StatusBadRequest, "missing results")
+		}
+		for _, result := range results {
+			result.Winner = result.GetScore() == best
+		}
+		if _, err := history.Modify(record.GetId(), func(record *matchpb.MatchRecord) error {
+			record.Results = results
+			return nil
+		}); err != nil {
+			return err
+		}
+		return respondCampaign(c, http.StatusOK, campaign, history)
+	}
+}
+
+func campaignName(campaign *matchpb.Campaign) string {
+	if campaign.GetName() != "" {
+		return campaign.GetName()
+	}
+	return "Campaign " + campaign.GetId()
+}
+
+func joinPoints(scoring []int32) string {
+	points := []string{}
+	for _, p := range scoring {
+		points = append(points, strconv.Itoa(int(p)))
+	}
+	return strings.Join(points, ", ")
+}
+
+// gamePlayers names the players of a game, with their factions once the
+// match is generated.
+func gamePlayers(game *matchpb.CampaignGame, record *matchpb.MatchRecord) string {
+	players := []string{}
+	for i, player := range game.GetPlayers() {
+		if i < len(record.GetMatch().GetPlayers()) {
+			player += " (" + record.GetMatch().GetPlayers()[i].GetName() + ")"
+		}
+		players = append(players, player)
+	}
+	return strings.Join(players, ", ")
+}
+
+func gameResults(record *matchpb.MatchRecord) string {
+	results := []string{}
+	for _, result := range record.GetResults() {
+		results = append(results, fmt.Sprintf("%s %d", result.GetPlayer(), result.GetScore()))
+	}
+	return strings.Join(results, ", ")
+}
+
+// resultsPlaceholder shows how the results form wants the game's results.
+func resultsPlaceholder(game *matchpb.CampaignGame, record *matchpb.MatchRecord) string {
+	results := []string{}
+	for i, player := range game.GetPlayers() {
+		if i < len(record.GetMatch().GetPlayers()) {
+			results = append(results, fmt.Sprintf("%s:%s:score", player, factionParamName(record.GetMatch().GetPlayers()[i].GetType())))
+		}
+	}
+	return strings.Join(results, " ")
+}
+
+func campaignsPageHandler(campaigns *Campaigns) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		cfg := defaultMatchCfg()
+		return renderComponent(c, campaignsPage(campaigns.List(), &cfg))
+	}
+}
+
+func campaignPageHandler(campaigns *Campaigns, history *History) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		campaign, err := campaignParam(c, campaigns)
+		if err != nil {
+			return err
+		}
+		records := map[string]*matchpb.MatchRecord{}
+		for _, game := range campaign.GetGames() {
+			if record, ok := history.Get(game.GetMatchId()); ok {
+				records[game.GetMatchId()] = record
+			}
+		}
+		return renderComponent(c, campaignPage(campaign, standings(campaign, history), records))
+	}
+}
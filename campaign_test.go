@@ -0,0 +1,290 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	"LegacyRoot/matchpb"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestScheduleSeason(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	cfg := defaultMatchCfg()
+	campaign, err := newCampaign(rng, "League", []string{"ana", "bo", "cy", "dee", "eli"}, defaultScoring, 4, 10, true, &cfg)
+	require.NoError(t, err)
+	require.Len(t, campaign.GetGames(), 10)
+
+	// Everybody sits out twice in a season of ten rounds.
+	scheduleSeason(rng, campaign)
+	require.Len(t, campaign.GetGames(), 20)
+	played := map[string]int{}
+	for i, game := range campaign.GetGames() {
+		assert.Len(t, game.GetPlayers(), 4)
+		assert.Equal(t, int32(i/10+1), game.GetSeason())
+		assert.Equal(t, int32(i%10+1), game.GetRound())
+		for _, player := range game.GetPlayers() {
+			played[player]++
+		}
+	}
+	for _, player := range campaign.GetRoster() {
+		assert.Equal(t, 16, played[player], player)
+	}
+
+	_, err = newCampaign(rng, "", nil, defaultScoring, 1, 1, false, &cfg)
+	assert.Error(t, err)
+	_, err = newCampaign(rng, "", []string{"ana", "ana"}, defaultScoring, 1, 1, false, &cfg)
+	assert.Error(t, err)
+	_, err = newCampaign(rng, "", []string{"ana", "bo"}, defaultScoring, 3, 1, false, &cfg)
+	assert.Error(t, err)
+	_, err = newCampaign(rng, "", []string{"ana", "bo"}, defaultScoring, 2, 0, false, &cfg)
+	assert.Error(t, err)
+}
+
+func TestCampaignEveryFaction(t *testing.T) {
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.pb"))
+	require.NoError(t, err)
+	cfg := defaultMatchCfg()
+	campaign, err := newCampaign(rand.New(rand.NewSource(2)), "", []string{"ana", "bo", "cy"}, defaultScoring, 3, int32(len(FactionNames)), true, &cfg)
+	require.NoError(t, err)
+
+	played := map[string]map[matchpb.FactionType]bool{}
+	for _, game := range campaign.GetGames() {
+		record, err := generateCampaignGame(history, campaign, game)
+		require.NoError(t, err)
+		game.MatchId = record.GetId()
+		require.Len(t, record.GetMatch().GetPlayers(), 3)
+		for i, player := range game.GetPlayers() {
+			if played[player] == nil {
+				played[player] = map[matchpb.FactionType]bool{}
+			}
+			played[player][record.GetMatch().GetPlayers()[i].GetType()] = true
+		}
+	}
+	for player, factions := range played {
+		assert.Len(t, factions, len(FactionNames), player)
+	}
+}
+
+func TestCampaignGameGenerator(t *testing.T) {
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.pb"))
+	require.NoError(t, err)
+	cfg := defaultMatchCfg()
+	cfg.BotEnemies = 1
+	campaign, err := newCampaign(rand.New(rand.NewSource(3)), "", []string{"ana", "bo"}, defaultScoring, 2, 1, false, &cfg)
+	require.NoError(t, err)
+
+	// The match is stored with the seed and config that generate it again,
+	// the campaign's factions included.
+	game := campaign.GetGames()[0]
+	record, err := generateCampaignGame(history, campaign, game)
+	require.NoError(t, err)
+	env := history.Envelopes()[0]
+	require.Len(t, env.GetConfig().GetFactions(), 2)
+	again, _, err := generateMatch(&matchpb.Match{}, matchCfgFromProto(env.GetConfig()), env.GetSeed())
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(record.GetMatch(), again))
+	for i, f := range env.GetConfig().GetFactions() {
+		assert.Equal(t, f, record.GetMatch().GetPlayers()[i].GetType())
+	}
+
+	// ADSET campaigns draft their factions, holding a militant one, and have
+	// no bots.
+	cfg.Setup = AdsetSetup
+	campaign.Config = matchCfgToProto(&cfg)
+	record, err = generateCampaignGame(history, campaign, game)
+	require.NoError(t, err)
+	assert.Empty(t, record.GetMatch().GetBots())
+	require.Len(t, record.GetMatch().GetPlayers(), 2)
+	assert.True(t, slices.ContainsFunc(record.GetMatch().GetPlayers(), func(p *matchpb.Faction) bool {
+		return slices.Contains(factionsOfClass(Militant), p.GetType())
+	}))
+}
+
+func TestStandings(t *testing.T) {
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.pb"))
+	require.NoError(t, err)
+	campaign := &matchpb.Campaign{Roster: []string{"ana", "bo", "cy", "dee"}, Scoring: defaultScoring}
+	results := [][]*matchpb.Result{
+		{{Player: "ana", Score: 30, Winner: true}, {Player: "bo", Score: 22}, {Player: "cy", Score: 22}, {Player: "dee", Score: 10}},
+		{{Player: "bo", Score: 30, Winner: true}, {Player: "dee", Score: 25}, {Player: "eve", Score: 28}},
+	}
+	for _, r := range results {
+		record, err := history.Add(&matchpb.Match{})
+		require.NoError(t, err)
+		_, err = history.Modify(record.GetId(), func(record *matchpb.MatchRecord) error {
+			record.Results = r
+			return nil
+		})
+		require.NoError(t, err)
+		campaign.Games = append(campaign.Games, &matchpb.CampaignGame{MatchId: record.GetId()})
+	}
+	campaign.Games = append(campaign.Games, &matchpb.CampaignGame{})
+
+	// Bo and Cy tie for second, and Eve isn't on the roster.
+	assert.Equal(t, []Standing{
+		{Player: "bo", Points: 5, Games: 2, Wins: 1},
+		{Player: "ana", Points: 3, Games: 1, Wins: 1},
+		{Player: "cy", Points: 2, Games: 1},
+		{Player: "dee", Points: 1, Games: 2},
+	}, standings(campaign, history))
+}
+
+func TestCampaignsStore(t *testing.T) {
+	for _, name := range []string{"campaigns.pb", "campaigns.jsonl"} {
+		path := filepath.Join(t.TempDir(), name)
+		campaigns, err := openCampaigns(path)
+		require.NoError(t, err)
+		campaign := &matchpb.Campaign{Name: "League", Roster: []string{"ana"}}
+		require.NoError(t, campaigns.Add(campaign))
+		require.NotEmpty(t, campaign.GetId())
+		campaign, err = campaigns.Modify(campaign.GetId(), func(campaign *matchpb.Campaign) error {
+			campaign.Games = []*matchpb.CampaignGame{{Season: 1, Round: 1, Players: []string{"ana"}}}
+			return nil
+		})
+		require.NoError(t, err)
+		_, err = campaigns.Modify(campaign.GetId(), func(campaign *matchpb.Campaign) error {
+			campaign.Name = "Cup"
+			return errors.New("failed")
+		})
+		assert.Error(t, err)
+		_, err = campaigns.Modify("missing", func(*matchpb.Campaign) error { return nil })
+		assert.Error(t, err)
+
+		reopened, err := openCampaigns(path)
+		require.NoError(t, err)
+		stored, ok := reopened.Get(campaign.GetId())
+		require.True(t, ok, name)
+		assert.True(t, proto.Equal(campaign, stored), name)
+	}
+}
+
+func TestCampaignsModifyConcurrently(t *testing.T) {
+	campaigns := &Campaigns{}
+	campaign := &matchpb.Campaign{Roster: []string{"ana"}}
+	require.NoError(t, campaigns.Add(campaign))
+
+	var wg sync.WaitGroup
+	for round := range int32(20) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := campaigns.Modify(campaign.GetId(), func(campaign *matchpb.Campaign) error {
+				campaign.Games = append(campaign.Games, &matchpb.CampaignGame{Season: 1, Round: round + 1})
+				return nil
+			})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+	// No change was lost to another.
+	stored, _ := campaigns.Get(campaign.GetId())
+	assert.Len(t, stored.GetGames(), 20)
+}
+
+func TestCampaignEndpoints(t *testing.T) {
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.pb"))
+	require.NoError(t, err)
+	e := newServer(history, &Campaigns{})
+	post := func(target string, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	assert.Equal(t, http.StatusBadRequest, post("/api/campaigns", url.Values{"roster": {"ana, bo"}, "table-size": {"3"}}).Code)
+	rec := post("/api/campaigns", url.Values{"name": {"League"}, "roster": {"ana, bo"}, "scoring": {"5, 1"}, "rounds": {"2"}, "bots": {"0"}})
+	require.Equal(t, http.StatusCreated, rec.Code)
+	created := campaignResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.Equal(t, []int32{5, 1}, created.Scoring)
+	require.Len(t, created.Games, 2)
+	base := "/api/campaigns/" + created.Id
+
+	assert.Equal(t, http.StatusConflict, post(base+"/games/0/results", url.Values{"results": {"ana:marquise:30"}}).Code)
+	assert.Equal(t, http.StatusNotFound, post(base+"/games/2/generate", nil).Code)
+	require.Equal(t, http.StatusOK, post(base+"/games/0/generate", nil).Code)
+	assert.Equal(t, http.StatusConflict, post(base+"/games/0/generate", nil).Code)
+
+	game := campaignResponse{}
+	require.NoError(t, json.Unmarshal(get(base).Body.Bytes(), &game))
+	record, ok := history.Get(game.Games[0].MatchId)
+	require.True(t, ok)
+	assert.Empty(t, record.GetMatch().GetBots())
+	players := game.Games[0].Players
+	results := []string{}
+	for i, f := range record.GetMatch().GetPlayers() {
+		results = append(results, players[i]+":"+factionParamName(f.GetType())+":"+[]string{"30", "20"}[i])
+	}
+	assert.Equal(t, http.StatusBadRequest, post(base+"/games/0/results", url.Values{"results": {"eve:marquise:30"}}).Code)
+	swapped := []string{players[0] + ":" + factionParamName(record.GetMatch().GetPlayers()[1].GetType()) + ":30"}
+	assert.Equal(t, http.StatusBadRequest, post(base+"/games/0/results", url.Values{"results": swapped}).Code)
+	require.Equal(t, http.StatusOK, post(base+"/games/0/results", url.Values{"results": {strings.Join(results, "\n")}}).Code)
+
+	response := campaignResponse{}
+	require.NoError(t, json.Unmarshal(get(base).Body.Bytes(), &response))
+	assert.Equal(t, Standing{Player: players[0], Points: 5, Games: 1, Wins: 1}, response.Standings[0])
+
+	require.Equal(t, http.StatusOK, post(base+"/seasons", nil).Code)
+	require.NoError(t, json.Unmarshal(get(base).Body.Bytes(), &response))
+	assert.Len(t, response.Games, 4)
+
+	// The pages answer forms with redirects.
+	rec = post("/campaigns", url.Values{"roster": {"cy"}})
+	require.Equal(t, http.StatusSeeOther, rec.Code)
+	page := rec.Header().Get(echo.HeaderLocation)
+	assert.Contains(t, get(page).Body.String(), "Schedule season 2")
+	assert.Contains(t, get("/campaigns").Body.String(), "League")
+	assert.Contains(t, get("/campaigns/"+created.Id).Body.String(), record.GetId())
+	assert.Equal(t, http.StatusNotFound, get("/campaigns/missing").Code)
+}
+
+func TestGenerateGameRemovesUnstoredMatch(t *testing.T) {
+	dir := t.TempDir()
+	history, err := openHistory(filepath.Join(dir, "history.pb"))
+	require.NoError(t, err)
+	campaigns, err := openCampaigns(filepath.Join(dir, "campaigns.json"))
+	require.NoError(t, err)
+	e := newServer(history, campaigns)
+	post := func(target string, form url.Values) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	rec := post("/api/campaigns", url.Values{"roster": {"ana, bo"}, "bots": {"0"}})
+	require.Equal(t, http.StatusCreated, rec.Code)
+	created := campaignResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+
+	// A directory in the way of the temporary file fails the campaign write.
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "campaigns.json.tmp", "blocked"), 0o755))
+	rec = post("/api/campaigns/"+created.Id+"/games/0/generate", nil)
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Empty(t, history.Records())
+	stored, ok := campaigns.Get(created.Id)
+	require.True(t, ok)
+	assert.Empty(t, stored.GetGames()[0].GetMatchId())
+}
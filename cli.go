@@ -0,0 +1,537 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"LegacyRoot/matchpb"
+	"LegacyRoot/render"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const usage = `usage: LegacyRoot <command> [flags]
+
+commands:
+  generate   generate a new match
+  history    list generated matches
+  stats      summarize the match history
+  record     record the results of a match
+  validate   check match files
+  import     import old match files or a CSV/TSV export into the history
+  migrate    upgrade a history file, optionally changing its format
+  export     export the match history as CSV or TSV
+  serve      run the web and gRPC servers
+
+Run LegacyRoot <command> -h for the flags of a command.`
+
+func runCLI(args []string, stdout io.Writer) error {
+	if len(args) == 0 {
+		return errors.New(usage)
+	}
+	commands := map[string]func([]string, io.Writer) error{
+		"generate": generateCmd,
+		"history":  historyCmd,
+		"stats":    statsCmd,
+		"record":   recordCmd,
+		"validate": validateCmd,
+		"import":   importCmd,
+		"migrate":  migrateCmd,
+		"export":   exportCmd,
+		"serve":    serveCmd,
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		return fmt.Errorf("unknown command %q\n%s", args[0], usage)
+	}
+	return cmd(args[1:], stdout)
+}
+
+// enumFlag sets a proto enum from its value name, e.g. -min-difficulty nightmare.
+type enumFlag[T ~int32] struct {
+	value *T
+	names map[string]int32
+}
+
+func (f enumFlag[T]) String() string {
+	if f.value == nil {
+		return ""
+	}
+	for name, v := range f.names {
+		if v == int32(*f.value) {
+			return name
+		}
+	}
+	return ""
+}
+
+func (f enumFlag[T]) Set(s string) error {
+	v, ok := f.names[strings.ToUpper(s)]
+	if !ok {
+		return fmt.Errorf("unknown value %q", s)
+	}
+	*f.value = T(v)
+	return nil
+}
+
+// matchCfgFlags registers a flag for every MatchCfg field.
+func matchCfgFlags(fs *flag.FlagSet) *MatchCfg {
+	cfg := defaultMatchCfg()
+	fs.BoolVar(&cfg.UseHirelings, "hirelings", cfg.UseHirelings, "pick hirelings")
+	fs.BoolVar(&cfg.UseLandmarks, "landmarks", cfg.UseLandmarks, "pick landmarks")
+	fs.BoolVar(&cfg.UseExilesDeck, "exiles-deck", cfg.UseExilesDeck, "let the Exiles & Partisans deck be picked")
+	fs.Func("bots", fmt.Sprintf("number of bot enemies (default %d)", cfg.BotEnemies), int32Flag(&cfg.BotEnemies))
+	fs.Func("players", fmt.Sprintf("number of human players (default %d)", cfg.Players), int32Flag(&cfg.Players))
+	fs.Var(enumFlag[matchpb.BotDifficulty]{&cfg.MinDifficulty, matchpb.BotDifficulty_value}, "min-difficulty", "lowest bot difficulty: easy, default, challenging or nightmare")
+	fs.Var(enumFlag[matchpb.BotDifficulty]{&cfg.MaxDifficulty, matchpb.BotDifficulty_value}, "max-difficulty", "highest bot difficulty: easy, default, challenging or nightmare")
+	fs.Func("target-challenge", "sum of the bot difficulty levels, 0 rolls each bot on its own", int32Flag(&cfg.TargetChallenge))
+	fs.Func("max-traits", fmt.Sprintf("most traits given to a bot (default %d)", cfg.MaxBotTraits), int32Flag(&cfg.MaxBotTraits))
+	fs.Float64Var(&cfg.MinScore, "min-score", cfg.MinScore, "lowest estimated match difficulty")
+	fs.Float64Var(&cfg.MaxScore, "max-score", cfg.MaxScore, "highest estimated match difficulty, 0 accepts any match")
+	fs.Var(enumFlag[PairingMode]{&cfg.Pairings, matchpb.PairingMode_value}, "pairings", "how bad pairings are handled: allow_pairings, down_weight_pairings or forbid_pairings")
+	fs.Float64Var(&cfg.BadPairing, "bad-pairing", cfg.BadPairing, "matchup score from which a pairing is bad")
+	fs.Var(enumFlag[SetupMode]{&cfg.Setup, matchpb.SetupMode_value}, "setup", "setup rules: standard_setup or adset")
+	fs.Var(enumFlag[SetupOrder]{&cfg.SetupOrder, matchpb.SetupOrder_value}, "setup-order", "order seats set up in: turn_order or faction_priority")
+	fs.BoolVar(&cfg.Coverage, "coverage", cfg.Coverage, "prefer factions, bots, hirelings, maps and landmarks not played yet")
+	fs.StringVar(&cfg.CoveragePlayer, "coverage-player", cfg.CoveragePlayer, "player whose coverage is preferred, the whole group by default")
+	return &cfg
+}
+
+func int32Flag(p *int32) func(string) error {
+	return func(s string) error {
+		v, err := strconv.ParseInt(s, 10, 32)
+		if err != nil {
+			return err
+		}
+		*p = int32(v)
+		return nil
+	}
+}
+
+// loadMatchupsFile replaces the built in matchup scores, a missing file keeps
+// them.
+func loadMatchupsFile(filename string) error {
+	if _, err := os.Stat(filename); err != nil {
+		return nil
+	}
+	matchups, err := loadMatchups(filename)
+	if err != nil {
+		return err
+	}
+	MatchupScores = matchups
+	return nil
+}
+
+func writeRecords(w io.Writer, format string, records []*matchpb.MatchRecord) error {
+	for i, record := range records {
+		if slices.Contains(render.Formats, format) {
+			if i > 0 {
+				fmt.Fprintln(w)
+			}
+			if err := render.Match(w, format, record.GetMatch()); err != nil {
+				return err
+			}
+			continue
+		}
+		switch format {
+		case "text":
+			printMatch(w, record)
+		case "json":
+			data, err := protojson.MarshalOptions{Multiline: true}.Marshal(record)
+			if err != nil {
+				return err
+			}
+			fmt.Fprintln(w, string(data))
+		case "binary":
+			if _, err := protodelim.MarshalTo(w, record); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("unknown format %q", format)
+		}
+	}
+	return nil
+}
+
+func printMatch(w io.Writer, record *matchpb.MatchRecord) {
+	match := record.GetMatch()
+	names := func(n int, name func(int) string) string {
+		list := []string{}
+		for i := range n {
+			list = append(list, name(i))
+		}
+		return strings.Join(list, ", ")
+	}
+
+	fmt.Fprintf(w, "Match %s (difficulty %.1f)\n", record.GetId(), matchDifficulty(match))
+	fmt.Fprintf(w, "Players: %s\n", names(len(match.GetPlayers()), func(i int) string { return match.GetPlayers()[i].GetName() }))
+	fmt.Fprintf(w, "Bots: %s\n", names(len(match.GetBots()), func(i int) string { return render.BotLabel(match.GetBots()[i]) }))
+	fmt.Fprintf(w, "Hirelings: %s\n", names(len(match.GetHirelings()), func(i int) string { return render.HirelingLabel(match.GetHirelings()[i]) }))
+	fmt.Fprintf(w, "Map: %s\n", match.GetMap().GetName())
+	fmt.Fprintf(w, "Deck: %s\n", render.DeckNames[match.GetDeck()])
+	fmt.Fprintf(w, "Landmarks: %s\n", names(len(match.GetLandmarks()), func(i int) string { return match.GetLandmarks()[i].GetName() }))
+	for _, line := range seatLines(match) {
+		fmt.Fprintln(w, line)
+	}
+}
+
+func generateCmd(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("generate", flag.ContinueOnError)
+	cfg := matchCfgFlags(fs)
+	historyPath := fs.String("history", "history.pb", "history file, protojson when it ends in .jsonl")
+	matchupsPath := fs.String("matchups", "matchups.json", "matchup matrix file")
+	format := fs.String("format", "text", "output format: text, json, binary, markdown, discord or ascii")
+	save := fs.Bool("save", true, "store the match in the history")
+	seed := fs.Int64("seed", 0, "seed to generate the match from, random when 0")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var err error
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == "bots" {
+			err = checkAdsetBots(cfg)
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	if err := loadMatchupsFile(*matchupsPath); err != nil {
+		return err
+	}
+	history, err := openHistory(*historyPath)
+	if err != nil {
+		return err
+	}
+	prev, err := previousMatch(history)
+	if err != nil {
+		return err
+	}
+	coverHistory(history, cfg)
+
+	if *seed == 0 {
+		*seed = newSeed()
+	}
+	match, used, err := generateMatch(prev, cfg, *seed)
+	if err != nil {
+		return err
+	}
+	record := &matchpb.MatchRecord{Match: match}
+	if *save {
+		record, err = history.AddGenerated(match, *seed, used)
+		if err != nil {
+			return err
+		}
+	}
+	return writeRecords(stdout, *format, []*matchpb.MatchRecord{record})
+}
+
+func historyCmd(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("history", flag.ContinueOnError)
+	historyPath := fs.String("history", "history.pb", "history file, protojson when it ends in .jsonl")
+	format := fs.String("format", "text", "output format: text, json, binary, markdown, discord or ascii")
+	limit := fs.Int("limit", 0, "most matches to list, newest first, 0 lists all")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	history, err := openHistory(*historyPath)
+	if err != nil {
+		return err
+	}
+	records := history.Records()
+	slices.Reverse(records)
+	if *limit > 0 && len(records) > *limit {
+		records = records[:*limit]
+	}
+	return writeRecords(stdout, *format, records)
+}
+
+func statsCmd(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("stats", flag.ContinueOnError)
+	historyPath := fs.String("history", "history.pb", "history file, protojson when it ends in .jsonl")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	history, err := openHistory(*historyPath)
+	if err != nil {
+		return err
+	}
+	records := history.Records()
+	players, bots, maps, wins := map[string]int{}, map[string]int{}, map[string]int{}, map[string]int{}
+	for _, record := range records {
+		for _, player := range record.GetMatch().GetPlayers() {
+			players[player.GetName()]++
+		}
+		for _, bot := range record.GetMatch().GetBots() {
+			bots[bot.GetName()]++
+		}
+		maps[record.GetMatch().GetMap().GetName()]++
+		for _, result := range record.GetResults() {
+			if result.GetWinner() {
+				wins[getFactionName(int32(result.GetFaction()))]++
+			}
+		}
+	}
+
+	tw := tabwriter.NewWriter(stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Matches:\t%d\n", len(records))
+	for _, section := range []struct {
+		title  string
+		counts map[string]int
+	}{{"Players", players}, {"Bots", bots}, {"Maps", maps}, {"Wins", wins}} {
+		fmt.Fprintf(tw, "%s:\n", section.title)
+		names := []string{}
+		for name := range section.counts {
+			names = append(names, name)
+		}
+		slices.SortFunc(names, func(a, b string) int {
+			if c := section.counts[b] - section.counts[a]; c != 0 {
+				return c
+			}
+			return strings.Compare(a, b)
+		})
+		for _, name := range names {
+			fmt.Fprintf(tw, "  %s\t%d\n", name, section.counts[name])
+		}
+	}
+	return tw.Flush()
+}
+
+// parseResult reads a result in the form name:FACTION:score.
+func parseResult(s string) (*matchpb.Result, error) {
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("result %q is not name:FACTION:score", s)
+	}
+	faction, ok := matchpb.FactionType_value[strings.ToUpper(parts[1])]
+	if !ok {
+		return nil, fmt.Errorf("unknown faction %q", parts[1])
+	}
+	score, err := strconv.ParseInt(parts[2], 10, 32)
+	if err != nil {
+		return nil, fmt.Errorf("invalid score %q: %w", parts[2], err)
+	}
+	return &matchpb.Result{Player: parts[0], Faction: matchpb.FactionType(faction), Score: int32(score)}, nil
+}
+
+func recordCmd(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("record", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: LegacyRoot record -id ID name:FACTION:score...")
+		fs.PrintDefaults()
+	}
+	historyPath := fs.String("history", "history.pb", "history file, protojson when it ends in .jsonl")
+	id := fs.String("id", "", "id of the match")
+	winner := fs.String("winner", "", "winning player, the highest score by default")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	history, err := openHistory(*historyPath)
+	if err != nil {
+		return err
+	}
+	record, ok := history.Get(*id)
+	if !ok {
+		return fmt.Errorf("no match with id %q", *id)
+	}
+
+	results := []*matchpb.Result{}
+	best := int32(0)
+	for _, arg := range fs.Args() {
+		result, err := parseResult(arg)
+		if err != nil {
+			return err
+		}
+		results = append(results, result)
+		best = max(best, result.GetScore())
+	}
+	if len(results) == 0 {
+		return errors.New("no results given")
+	}
+	for _, result := range results {
+		if *winner != "" {
+			result.Winner = result.GetPlayer() == *winner
+		} else {
+			result.Winner = result.GetScore() == best
+		}
+	}
+
+	record, err = history.Modify(record.GetId(), func(record *matchpb.MatchRecord) error {
+		record.Results = results
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Recorded %d results for match %s\n", len(results), record.GetId())
+	return nil
+}
+
+func validateCmd(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("validate", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: LegacyRoot validate file...")
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	failed := false
+	for _, filename := range fs.Args() {
+		_, err := parseMatch(filename)
+		var violations ValidationError
+		if errors.As(err, &violations) {
+			for _, v := range violations {
+				fmt.Fprintf(stdout, "%s: %s: %s\n", filename, v.Rule, v.Message)
+			}
+			failed = true
+			continue
+		}
+		if err != nil {
+			fmt.Fprintf(stdout, "%s: %v\n", filename, err)
+			failed = true
+			continue
+		}
+		fmt.Fprintf(stdout, "%s: ok\n", filename)
+	}
+	if failed {
+		return errors.New("validation failed")
+	}
+	return nil
+}
+
+func importCmd(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("import", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: LegacyRoot import [flags] dir|export.csv|export.tsv")
+		fs.PrintDefaults()
+	}
+	historyPath := fs.String("history", "history.pb", "history file, protojson when it ends in .jsonl")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return errors.New("expected a directory or export file to import")
+	}
+
+	history, err := openHistory(*historyPath)
+	if err != nil {
+		return err
+	}
+	if comma, ok := exportSeparator(fs.Arg(0)); ok {
+		f, err := os.Open(fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		added, err := importCSV(f, comma, history)
+		fmt.Fprintf(stdout, "Imported %d matches from %s\n", added, fs.Arg(0))
+		return err
+	}
+	results, err := importDir(fs.Arg(0), history)
+	failed := 0
+	for _, result := range results {
+		if result.Err != nil {
+			fmt.Fprintf(stdout, "%s: %v\n", result.File, result.Err)
+			failed++
+		} else if result.Skipped {
+			fmt.Fprintf(stdout, "%s: already imported as %s\n", result.File, result.Id)
+		} else {
+			fmt.Fprintf(stdout, "%s: imported as %s\n", result.File, result.Id)
+		}
+		for _, fix := range result.Fixes {
+			fmt.Fprintf(stdout, "  %s\n", fix)
+		}
+	}
+	if err == nil && failed > 0 {
+		return fmt.Errorf("%d of %d files failed to import", failed, len(results))
+	}
+	return err
+}
+
+// exportSeparator returns the separator for a CSV or TSV export file.
+func exportSeparator(filename string) (rune, bool) {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv":
+		return ',', true
+	case ".tsv":
+		return '\t', true
+	}
+	return 0, false
+}
+
+func exportCmd(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	historyPath := fs.String("history", "history.pb", "history file, protojson when it ends in .jsonl")
+	format := fs.String("format", "csv", "export format: csv or tsv")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	comma, ok := exportSeparator("." + *format)
+	if !ok {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+	history, err := openHistory(*historyPath)
+	if err != nil {
+		return err
+	}
+	return writeMatchesCSV(stdout, history.Records(), comma)
+}
+
+func migrateCmd(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("migrate", flag.ContinueOnError)
+	from := fs.String("from", "history.jsonl", "history file to upgrade")
+	to := fs.String("to", "history.pb", "file to write the upgraded history to, protojson when it ends in .jsonl")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	history, err := openHistory(*from)
+	if err != nil {
+		return err
+	}
+	if err := history.SaveAs(*to); err != nil {
+		return err
+	}
+	fmt.Fprintf(stdout, "Migrated %d matches from %s to %s\n", len(history.Records()), *from, *to)
+	return nil
+}
+
+func serveCmd(args []string, stdout io.Writer) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	historyPath := fs.String("history", "history.pb", "history file, protojson when it ends in .jsonl")
+	matchupsPath := fs.String("matchups", "matchups.json", "matchup matrix file")
+	addr := fs.String("addr", ":1323", "web server address")
+	grpcAddr := fs.String("grpc-addr", ":1324", "gRPC server address")
+	campaignsPath := fs.String("campaigns", "campaigns.pb", "campaigns file, protojson when it ends in .jsonl")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if err := loadMatchupsFile(*matchupsPath); err != nil {
+		return err
+	}
+	history, err := openHistory(*historyPath)
+	if err != nil {
+		return err
+	}
+	campaigns, err := openCampaigns(*campaignsPath)
+	if err != nil {
+		return err
+	}
+	return serve(history, campaigns, *addr, *grpcAddr)
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"LegacyRoot/matchpb"
+	"bytes"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protodelim"
+)
+
+func TestCLIGenerateFormats(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "history.jsonl")
+
+	var out bytes.Buffer
+	err := runCLI([]string{"generate", "-history", historyPath, "-bots", "2", "-min-difficulty", "challenging"}, &out)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "Players: ")
+	assert.Regexp(t, "Deck: (Base|Exiles & Partisans)\n", out.String())
+	bots := regexp.MustCompile("Bots: .*\n").FindString(out.String())
+	assert.Equal(t, 2, strings.Count(bots, "(Challenging)")+strings.Count(bots, "(Nightmare)"))
+	assert.Regexp(t, "Seat 1: .*, sets up (1st|2nd|3rd)( in clearing \\d+)?\n", out.String())
+	assert.Equal(t, 1, strings.Count(out.String(), ", first player"))
+
+	out.Reset()
+	err = runCLI([]string{"generate", "-history", historyPath, "-format", "binary", "-hirelings=false"}, &out)
+	require.NoError(t, err)
+	record := &matchpb.MatchRecord{}
+	require.NoError(t, protodelim.UnmarshalFrom(&out, record))
+	assert.NotEmpty(t, record.GetId())
+	assert.Empty(t, record.GetMatch().GetHirelings())
+
+	out.Reset()
+	err = runCLI([]string{"history", "-history", historyPath, "-format", "json", "-limit", "1"}, &out)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), record.GetId())
+
+	out.Reset()
+	err = runCLI([]string{"history", "-history", historyPath, "-format", "ascii"}, &out)
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(out.String(), "Root setup, "))
+
+	err = runCLI([]string{"generate", "-history", historyPath, "-format", "yaml"}, &out)
+	assert.Error(t, err)
+
+	// ADSET matches have no bots, asking for them is an error rather than
+	// the default single bot being dropped.
+	out.Reset()
+	require.NoError(t, runCLI([]string{"generate", "-history", historyPath, "-setup", "adset"}, &out))
+	assert.Contains(t, out.String(), "Bots: \n")
+	err = runCLI([]string{"generate", "-history", historyPath, "-setup", "adset", "-bots", "2"}, &out)
+	assert.ErrorContains(t, err, "ADSET matches have no bots")
+}
+
+func TestCLIRecordAndStats(t *testing.T) {
+	historyPath := filepath.Join(t.TempDir(), "history.jsonl")
+	history, err := openHistory(historyPath)
+	require.NoError(t, err)
+	record, err := history.Add(&matchpb.Match{
+		Players: []*matchpb.Faction{NewFaction(Marquise)},
+		Map:     &matchpb.MapVal{Type: matchpb.MapType_LAKE, Name: "Lake"},
+	})
+	require.NoError(t, err)
+
+	var out bytes.Buffer
+	err = runCLI([]string{"record", "-history", historyPath, "-id", record.GetId(), "ana:marquise:30", "bo:eyrie:25"}, &out)
+	require.NoError(t, err)
+
+	history, err = openHistory(historyPath)
+	require.NoError(t, err)
+	got, _ := history.Get(record.GetId())
+	require.Len(t, got.GetResults(), 2)
+	assert.True(t, got.GetResults()[0].GetWinner())
+	assert.False(t, got.GetResults()[1].GetWinner())
+
+	out.Reset()
+	err = runCLI([]string{"stats", "-history", historyPath}, &out)
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "Lake  1")
+	assert.Contains(t, out.String(), "Wins:\n  Marquise de Cat  1")
+
+	err = runCLI([]string{"record", "-history", historyPath, "-id", "missing", "ana:marquise:30"}, &out)
+	assert.Error(t, err)
+}
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"LegacyRoot/matchpb"
+
+	"github.com/labstack/echo"
+	"google.golang.org/protobuf/proto"
+)
+
+// Weight kept by an item the coverage goal has already seen, while some of
+// its kind are still left to play.
+const coveredWeight = 0.02
+
+// Coverage is what has been played towards the goal of playing everything at
+// least once, keyed like the pools the generator picks from.
+type Coverage struct {
+	Factions  map[int32]bool
+	Bots      map[int32]bool
+	Hirelings map[int32]bool
+	Maps      map[int32]bool
+	Landmarks map[int32]bool
+}
+
+// playedCoverage gathers what player has played, everyone together when
+// player is empty. Only matches with results were played, and a player only
+// covers the faction they played themselves.
+func playedCoverage(records []*matchpb.MatchRecord, player string) Coverage {
+	covered := Coverage{
+		Factions:  map[int32]bool{},
+		Bots:      map[int32]bool{},
+		Hirelings: map[int32]bool{},
+		Maps:      map[int32]bool{},
+		Landmarks: map[int32]bool{},
+	}
+	for _, record := range records {
+		played := false
+		for _, result := range record.GetResults() {
+			if player == "" || result.GetPlayer() == player {
+				covered.Factions[int32(result.GetFaction())] = true
+				played = true
+			}
+		}
+		if !played {
+			continue
+		}
+		match := record.GetMatch()
+		for _, bot := range match.GetBots() {
+			if bot.GetClockwork() != matchpb.BotType_BOT_TYPE_UNSPECIFIED {
+				covered.Bots[int32(bot.GetClockwork())] = true
+			}
+		}
+		for _, hireling := range match.GetHirelings() {
+			covered.Hirelings[int32(hireling.GetType())] = true
+		}
+		if match.GetMap() != nil {
+			covered.Maps[int32(match.GetMap().GetType())] = true
+		}
+		for _, landmark := range match.GetLandmarks() {
+			covered.Landmarks[int32(landmark.GetType())] = true
+		}
+	}
+	return covered
+}
+
+// preferUncovered scales down the weight of covered items, leaving the
+// weights alone once every item is covered.
+func preferUncovered(items []Item, covered map[int32]bool) []Item {
+	if !slices.ContainsFunc(items, func(item Item) bool { return !covered[item.Name] }) {
+		return items
+	}
+	weighted := slices.Clone(items)
+	for i := range weighted {
+		if covered[weighted[i].Name] {
+			weighted[i].Weight *= coveredWeight
+		}
+	}
+	return weighted
+}
+
+func coveredItems(covered map[int32]bool) []int32 {
+	items := []int32{}
+	for _, item := range sortedKeys(covered) {
+		if covered[item] {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+func coveredSet(items []int32) map[int32]bool {
+	if len(items) == 0 {
+		return nil
+	}
+	covered := map[int32]bool{}
+	for _, item := range items {
+		covered[item] = true
+	}
+	return covered
+}
+
+// coverageToProto lists what is covered, nil when nothing is.
+func coverageToProto(covered Coverage) *matchpb.CoveredItems {
+	items := &matchpb.CoveredItems{
+		Factions:  coveredItems(covered.Factions),
+		Bots:      coveredItems(covered.Bots),
+		Hirelings: coveredItems(covered.Hirelings),
+		Maps:      coveredItems(covered.Maps),
+		Landmarks: coveredItems(covered.Landmarks),
+	}
+	if proto.Size(items) == 0 {
+		return nil
+	}
+	return items
+}
+
+func coverageFromProto(covered *matchpb.CoveredItems) Coverage {
+	return Coverage{
+		Factions:  coveredSet(covered.GetFactions()),
+		Bots:      coveredSet(covered.GetBots()),
+		Hirelings: coveredSet(covered.GetHirelings()),
+		Maps:      coveredSet(covered.GetMaps()),
+		Landmarks: coveredSet(covered.GetLandmarks()),
+	}
+}
+
+// coverHistory fills in what cfg's coverage goal has already seen in the
+// history, leaving cfg alone when the goal is off.
+func coverHistory(history *History, cfg *MatchCfg) {
+	if cfg.Coverage {
+		cfg.Covered = playedCoverage(history.Records(), cfg.CoveragePlayer)
+	}
+}
+
+// CoverageSection is the progress on one kind of item.
+type CoverageSection struct {
+	Name    string   `json:"name"`
+	Covered int      `json:"covered"`
+	Total   int      `json:"total"`
+	Missing []string `json:"missing"`
+}
+
+// CoverageProgress is a player's progress towards playing everything, an
+// empty player standing for the whole group.
+type CoverageProgress struct {
+	Player   string            `json:"player"`
+	Sections []CoverageSection `json:"sections"`
+}
+
+func (p CoverageProgress) Complete() bool {
+	for _, section := range p.Sections {
+		if section.Covered < section.Total {
+			return false
+		}
+	}
+	return true
+}
+
+func coverageSection(name string, all []int32, covered map[int32]bool, label func(int32) string) CoverageSection {
+	section := CoverageSection{Name: name, Total: len(all), Missing: []string{}}
+	for _, item := range all {
+		if covered[item] {
+			section.Covered++
+		} else {
+			section.Missing = append(section.Missing, label(item))
+		}
+	}
+	return section
+}
+
+func coverageProgress(records []*matchpb.MatchRecord, player string) CoverageProgress {
+	covered := playedCoverage(records, player)
+	bots := []int32{}
+	for _, b := range sortedKeys(BotCatalog) {
+		bots = append(bots, int32(b))
+	}
+	landmarks := slices.Clone(Landmarks)
+	slices.Sort(landmarks)
+	return CoverageProgress{Player: player, Sections: []CoverageSection{
+		coverageSection("Factions", sortedKeys(FactionNames), covered.Factions, getFactionName),
+		coverageSection("Bots", bots, covered.Bots, func(b int32) string { return BotCatalog[matchpb.BotType(b)].Name }),
+		coverageSection("Hirelings", sortedKeys(Hirelings), covered.Hirelings, func(f int32) string { return strings.Join(Hirelings[f], " / ") }),
+		coverageSection("Maps", sortedKeys(MapNames), covered.Maps, func(m int32) string { return MapNames[m] }),
+		coverageSection("Landmarks", landmarks, covered.Landmarks, getLandmarkName),
+	}}
+}
+
+// coveragePlayers lists everyone with a recorded result, in order of their
+// first one.
+func coveragePlayers(records []*matchpb.MatchRecord) []string {
+	players := []string{}
+	for _, record := range records {
+		for _, result := range record.GetResults() {
+			if result.GetPlayer() != "" && !slices.Contains(players, result.GetPlayer()) {
+				players = append(players, result.GetPlayer())
+			}
+		}
+	}
+	return players
+}
+
+// allCoverage is the group's progress followed by every player's, or just
+// player's when one is given.
+func allCoverage(records []*matchpb.MatchRecord, player string) []CoverageProgress {
+	if player != "" {
+		return []CoverageProgress{coverageProgress(records, player)}
+	}
+	progress := []CoverageProgress{coverageProgress(records, "")}
+	for _, p := range coveragePlayers(records) {
+		progress = append(progress, coverageProgress(records, p))
+	}
+	return progress
+}
+
+func coverageHandler(history *History) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return c.JSON(http.StatusOK, allCoverage(history.Records(), c.QueryParam("player")))
+	}
+}
+
+func coveragePageHandler(history *History) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		return renderComponent(c, coveragePage(allCoverage(history.Records(), c.QueryParam("player"))))
+	}
+}
+
+func coverageTitle(p CoverageProgress) string {
+	if p.Player == "" {
+		return "Everyone"
+	}
+	return p.Player
+}
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"encoding/json"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"LegacyRoot/matchpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func coverageRecords() []*matchpb.MatchRecord {
+	return []*matchpb.MatchRecord{
+		{
+			Match: &matchpb.Match{
+				Map:       &matchpb.MapVal{Type: matchpb.MapType_LAKE},
+				Bots:      []*matchpb.Bot{{Type: matchpb.FactionType_EYRIE, Clockwork: matchpb.BotType_ELECTRIC_EYRIE}},
+				Hirelings: []*matchpb.Hireling{{Type: matchpb.FactionType_CORVID}},
+				Landmarks: []*matchpb.Landmark{{Type: matchpb.LandmarkType_FERRY}},
+			},
+			Results: []*matchpb.Result{{Player: "ana", Faction: matchpb.FactionType_MARQUISE}, {Player: "bo", Faction: matchpb.FactionType_ALLIANCE}},
+		},
+		{
+			Match: &matchpb.Match{
+				Map:  &matchpb.MapVal{Type: matchpb.MapType_WINTER},
+				Bots: []*matchpb.Bot{{Type: matchpb.FactionType_LIZARD, Clockwork: matchpb.BotType_LOGICAL_LIZARDS}},
+			},
+			Results: []*matchpb.Result{{Player: "bo", Faction: matchpb.FactionType_VAGABOND}},
+		},
+		// Matches without results weren't played.
+		{Match: &matchpb.Match{Map: &matchpb.MapVal{Type: matchpb.MapType_MOUNTAIN}}},
+	}
+}
+
+func TestPlayedCoverage(t *testing.T) {
+	records := coverageRecords()
+
+	ana := playedCoverage(records, "ana")
+	assert.Equal(t, map[int32]bool{Marquise: true}, ana.Factions)
+	assert.Equal(t, map[int32]bool{int32(matchpb.BotType_ELECTRIC_EYRIE): true}, ana.Bots)
+	assert.Equal(t, map[int32]bool{Corvid: true}, ana.Hirelings)
+	assert.Equal(t, map[int32]bool{Lake: true}, ana.Maps)
+	assert.Equal(t, map[int32]bool{Ferry: true}, ana.Landmarks)
+
+	everyone := playedCoverage(records, "")
+	assert.Equal(t, map[int32]bool{Marquise: true, Alliance: true, Vagabond: true}, everyone.Factions)
+	assert.Equal(t, map[int32]bool{Lake: true, Winter: true}, everyone.Maps)
+	assert.Len(t, everyone.Bots, 2)
+
+	assert.Empty(t, playedCoverage(records, "eve").Maps)
+}
+
+func TestPreferUncovered(t *testing.T) {
+	items := []Item{{Name: 1, Weight: 0.5}, {Name: 2, Weight: 0.5}}
+	assert.Equal(t, []Item{{Name: 1, Weight: 0.5 * coveredWeight}, {Name: 2, Weight: 0.5}}, preferUncovered(items, map[int32]bool{1: true}))
+	assert.Equal(t, 0.5, items[0].Weight)
+
+	// Once everything is covered the weights are left alone.
+	assert.Equal(t, items, preferUncovered(items, map[int32]bool{1: true, 2: true}))
+	assert.Equal(t, items, preferUncovered(items, nil))
+}
+
+func TestCoverageGeneration(t *testing.T) {
+	cfg := defaultMatchCfg()
+	cfg.Coverage = true
+	cfg.Covered = Coverage{Factions: map[int32]bool{}, Maps: map[int32]bool{}}
+	for f := range FactionNames {
+		cfg.Covered.Factions[f] = f != Keepers
+	}
+	for m := range MapNames {
+		cfg.Covered.Maps[m] = m != Mountain
+	}
+
+	keepers, mountain := 0, 0
+	for seed := range int64(100) {
+		match, _, err := generateMatch(&matchpb.Match{}, &cfg, seed)
+		require.NoError(t, err)
+		if match.GetPlayers()[0].GetType() == matchpb.FactionType_KEEPERS {
+			keepers++
+		}
+		if match.GetMap().GetType() == matchpb.MapType_MOUNTAIN {
+			mountain++
+		}
+	}
+	assert.Greater(t, keepers, 70)
+	assert.Greater(t, mountain, 80)
+}
+
+func TestCoverageReproducible(t *testing.T) {
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.pb"))
+	require.NoError(t, err)
+	for _, r := range coverageRecords() {
+		record, err := history.Add(r.GetMatch())
+		require.NoError(t, err)
+		_, err = history.Modify(record.GetId(), func(record *matchpb.MatchRecord) error {
+			record.Results = r.GetResults()
+			return nil
+		})
+		require.NoError(t, err)
+	}
+	prev := history.Last()
+	cfg := defaultMatchCfg()
+	cfg.Coverage = true
+	record, err := generateStored(history, &cfg)
+	require.NoError(t, err)
+
+	// The covered items are stored with the config, so the seed and config
+	// generate the same match after the history moved on.
+	env := history.Envelopes()[len(history.Envelopes())-1]
+	assert.Equal(t, []int32{Winter, Lake}, env.GetConfig().GetCovered().GetMaps())
+	stored := matchCfgFromProto(env.GetConfig())
+	assert.Equal(t, cfg.Covered, stored.Covered)
+	again, _, err := generateMatch(prev, stored, env.GetSeed())
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(record.GetMatch(), again))
+
+	// Campaign games follow the coverage goal too.
+	campaign, err := newCampaign(rand.New(rand.NewSource(1)), "", []string{"ana", "bo"}, defaultScoring, 2, 1, false, &cfg)
+	require.NoError(t, err)
+	_, err = generateCampaignGame(history, campaign, campaign.GetGames()[0])
+	require.NoError(t, err)
+	env = history.Envelopes()[len(history.Envelopes())-1]
+	assert.Equal(t, []int32{Winter, Lake}, env.GetConfig().GetCovered().GetMaps())
+}
+
+func TestCoverageEndpoints(t *testing.T) {
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.pb"))
+	require.NoError(t, err)
+	for _, r := range coverageRecords() {
+		record, err := history.Add(r.GetMatch())
+		require.NoError(t, err)
+		_, err = history.Modify(record.GetId(), func(record *matchpb.MatchRecord) error {
+			record.Results = r.GetResults()
+			return nil
+		})
+		require.NoError(t, err)
+	}
+	e := newServer(history, &Campaigns{})
+	get := func(target string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, target, nil))
+		return rec
+	}
+
+	progress := []CoverageProgress{}
+	require.NoError(t, json.Unmarshal(get("/api/coverage").Body.Bytes(), &progress))
+	require.Len(t, progress, 3)
+	assert.Equal(t, []string{"", "ana", "bo"}, []string{progress[0].Player, progress[1].Player, progress[2].Player})
+	assert.Equal(t, CoverageSection{Name: "Maps", Covered: 2, Total: 4, Missing: []string{"Autumn", "Mountain"}}, progress[0].Sections[3])
+
+	require.NoError(t, json.Unmarshal(get("/api/coverage?player=bo").Body.Bytes(), &progress))
+	require.Len(t, progress, 1)
+	assert.Equal(t, 2, progress[0].Sections[0].Covered)
+
+	page := get("/coverage").Body.String()
+	assert.Contains(t, page, "Everyone")
+	assert.Contains(t, page, "Mechanical Marquise 2.0")
+}
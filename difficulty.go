@@ -0,0 +1,124 @@
+package main
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"math/rand"
+	"time"
+
+	"LegacyRoot/matchpb"
+)
+
+// How much harder a bot faction makes the game for a player faction, on top of
+// the bot's own difficulty. Missing pairings count as even.
+var MatchupScores = map[int32]map[int32]float64{
+	Marquise:    {Alliance: 0.5, Vagabond: 0.25, Lizard: 0.25},
+	Eyrie:       {Marquise: 0.5, Alliance: 0.25, Corvid: 0.25},
+	Alliance:    {Marquise: 0.5, Eyrie: 0.25},
+	Vagabond:    {Eyrie: 0.25, Riverfolk: -0.25},
+	Riverfolk:   {Corvid: 0.5, Vagabond: 0.25},
+	Lizard:      {Eyrie: 0.5, Underground: 0.25},
+	Underground: {Marquise: 0.5, Alliance: 0.25},
+	Corvid:      {Marquise: 0.25, Vagabond: 0.5},
+	Hundreds:    {Alliance: 0.5, Lizard: 0.25},
+	Keepers:     {Underground: 0.5, Corvid: 0.25},
+}
+
+var MapScores = map[matchpb.MapType]float64{
+	matchpb.MapType_AUTUMN:   0,
+	matchpb.MapType_WINTER:   0.5,
+	matchpb.MapType_LAKE:     0.5,
+	matchpb.MapType_MOUNTAIN: 1,
+}
+
+// Attempts made to land a match inside the configured difficulty band.
+const maxResamples = 200
+
+// matchDifficulty estimates how hard a match is for the human seat. Every bot
+// adds to it according to its difficulty, traits and how it pairs with the
+// player's faction, hirelings and harder maps add a little on top.
+func matchDifficulty(match *matchpb.Match) float64 {
+	score := 0.0
+	for _, bot := range match.GetBots() {
+		score += 1 + 0.75*float64(bot.GetDifficulty()) + 0.25*float64(len(bot.GetTraits()))
+		for _, player := range match.GetPlayers() {
+			score += MatchupScores[int32(player.GetType())][int32(bot.GetType())]
+		}
+	}
+	score += 0.25 * float64(len(match.GetHirelings()))
+	score += MapScores[match.GetMap().GetType()]
+	return math.Round(score*10) / 10
+}
+
+func inDifficultyBand(score float64, cfg *MatchCfg) bool {
+	if cfg.MaxScore <= 0 {
+		return true
+	}
+	return score >= cfg.MinScore && score <= cfg.MaxScore
+}
+
+func newSeed() int64 {
+	return time.Now().UnixNano()
+}
+
+// generateMatch generates a match from the full catalogs, resampling until its
+// difficulty falls inside the configured band. When no sample lands inside the
+// band the closest one is returned. Every sample has to pass Validate, and
+// when no sample has enough bots to face its players that is the error. The
+// same seed, config and previous match always generate the same match. ADSET
+// matches follow their own rules and aren't resampled. The config the match
+// was generated with is returned to be stored with it, see usedCfg.
+func generateMatch(prev *matchpb.Match, cfg *MatchCfg, seed int64) (*matchpb.Match, *MatchCfg, error) {
+	rng := rand.New(rand.NewSource(seed))
+	cfg = usedCfg(cfg)
+	if cfg.Setup == AdsetSetup {
+		match, err := generateAdsetMatch(rng, cfg)
+		if err != nil {
+			return nil, nil, err
+		}
+		if violations := Validate(match); len(violations) > 0 {
+			return nil, nil, fmt.Errorf("generated %w", ValidationError(violations))
+		}
+		return match, cfg, nil
+	}
+	var best *matchpb.Match
+	var lastErr error
+	bestDistance := math.Inf(1)
+	for range maxResamples {
+		match, err := generateNewMatch(rng, prev, maps.Clone(FactionNames), maps.Clone(BotCatalog), maps.Clone(Hirelings), cfg)
+		if err != nil {
+			// Other players may leave enough bots to face them.
+			lastErr = err
+			continue
+		}
+		if violations := Validate(match); len(violations) > 0 {
+			return nil, nil, fmt.Errorf("generated %w", ValidationError(violations))
+		}
+		score := matchDifficulty(match)
+		if inDifficultyBand(score, cfg) {
+			return match, cfg, nil
+		}
+		distance := math.Min(math.Abs(score-cfg.MinScore), math.Abs(score-cfg.MaxScore))
+		if distance < bestDistance {
+			best, bestDistance = match, distance
+		}
+	}
+	if best == nil {
+		return nil, nil, lastErr
+	}
+	return best, cfg, nil
+}
+
+// usedCfg is a copy of cfg with what its setup rules change: ADSET raises
+// fewer players, the single player other matches default to included, to
+// what it needs, and has no bots or hirelings.
+func usedCfg(cfg *MatchCfg) *MatchCfg {
+	used := *cfg
+	if used.Setup == AdsetSetup {
+		used.Players = max(used.Players, minAdsetPlayers)
+		used.BotEnemies = 0
+		used.UseHirelings = false
+	}
+	return &used
+}
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"LegacyRoot/matchpb"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMatchDifficulty(t *testing.T) {
+	match := &matchpb.Match{
+		Players: []*matchpb.Faction{{Type: matchpb.FactionType_MARQUISE}},
+		Bots: []*matchpb.Bot{
+			{Type: matchpb.FactionType_ALLIANCE, Difficulty: matchpb.BotDifficulty_CHALLENGING, Traits: []string{"Wildfire"}},
+			{Type: matchpb.FactionType_RIVERFOLK, Difficulty: matchpb.BotDifficulty_EASY},
+		},
+		Hirelings: []*matchpb.Hireling{{}, {}},
+		Map:       &matchpb.MapVal{Type: matchpb.MapType_MOUNTAIN},
+	}
+	// Alliance: 1 + 1.5 + 0.25 + 0.5 matchup, Riverfolk: 1, hirelings 0.5, map 1.
+	assert.Equal(t, 5.8, matchDifficulty(match))
+}
+
+func TestGenerateMatchDifficultyBand(t *testing.T) {
+	prev, err := parseMatch("match.json")
+	assert.NoError(t, err)
+	cfg := &MatchCfg{
+		Players:       1,
+		BotEnemies:    2,
+		MinDifficulty: matchpb.BotDifficulty_EASY,
+		MaxDifficulty: matchpb.BotDifficulty_NIGHTMARE,
+		MinScore:      5,
+		MaxScore:      6,
+	}
+	for range 10 {
+		match, _, err := generateMatch(prev, cfg, newSeed())
+		assert.NoError(t, err)
+		score := matchDifficulty(match)
+		assert.GreaterOrEqual(t, score, 5.0)
+		assert.LessOrEqual(t, score, 6.0)
+	}
+}
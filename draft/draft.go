@@ -0,0 +1,290 @@
+// Package draft runs a faction draft. A hand of one faction more than there
+// are players is dealt, and the players pick from it in reverse seat order,
+// so the first seat gets the last choice and the leftover faction is out.
+package draft
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"slices"
+	"time"
+
+	"LegacyRoot/matchpb"
+)
+
+// Reach is each faction's reach, how much it does to hold the others back.
+var Reach = map[matchpb.FactionType]int{
+	matchpb.FactionType_MARQUISE:    10,
+	matchpb.FactionType_EYRIE:       7,
+	matchpb.FactionType_ALLIANCE:    3,
+	matchpb.FactionType_VAGABOND:    5,
+	matchpb.FactionType_RIVERFOLK:   5,
+	matchpb.FactionType_LIZARD:      2,
+	matchpb.FactionType_UNDERGROUND: 8,
+	matchpb.FactionType_CORVID:      3,
+	matchpb.FactionType_HUNDREDS:    9,
+	matchpb.FactionType_KEEPERS:     8,
+}
+
+// MinReach is the total reach a table of that many players needs.
+var MinReach = map[int]int{2: 17, 3: 18, 4: 21, 5: 25, 6: 28}
+
+func minReach(players int) int {
+	switch {
+	case players < 2:
+		return 0
+	case players > 6:
+		return MinReach[6]
+	}
+	return MinReach[players]
+}
+
+// deals is how many hands Deal tries before giving up on the reach.
+const deals = 100
+
+var (
+	ErrNoHand        = errors.New("no hand of factions has enough reach")
+	ErrDone          = errors.New("the draft is over")
+	ErrNotYourTurn   = errors.New("not this seat's turn")
+	ErrNotInHand     = errors.New("faction isn't in the hand")
+	ErrNotAllowed    = errors.New("the picks left couldn't reach the table's total or take a required faction")
+	ErrNothingToUndo = errors.New("nothing to undo")
+)
+
+// Deal deals a hand of players+1 factions from pool, leaving out excluded
+// ones, redealing until the players can pick factions that reach the total
+// the table needs.
+func Deal(rng *rand.Rand, players int, pool, exclude []matchpb.FactionType) ([]matchpb.FactionType, error) {
+	return DealRequiring(rng, players, pool, exclude, nil)
+}
+
+// DealRequiring is Deal for picks that also take at least one of the
+// required factions, none required accepting any.
+func DealRequiring(rng *rand.Rand, players int, pool, exclude, required []matchpb.FactionType) ([]matchpb.FactionType, error) {
+	available := []matchpb.FactionType{}
+	for _, f := range pool {
+		if !slices.Contains(exclude, f) && !slices.Contains(available, f) {
+			available = append(available, f)
+		}
+	}
+	slices.Sort(available)
+	if len(available) < players+1 {
+		return nil, fmt.Errorf("%w: %d factions left for %d players", ErrNoHand, len(available), players)
+	}
+
+	for range deals {
+		hand := []matchpb.FactionType{}
+		for _, i := range rng.Perm(len(available))[:players+1] {
+			hand = append(hand, available[i])
+		}
+		if completes(nil, hand, required, players, minReach(players)) {
+			return hand, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %d players need a reach of %d", ErrNoHand, players, minReach(players))
+}
+
+// completes reports whether picking seats more factions out of hand can take
+// picked to a total reach of need with one of the required factions.
+func completes(picked, hand, required []matchpb.FactionType, seats, need int) bool {
+	if seats == 0 {
+		total := 0
+		for _, f := range picked {
+			total += Reach[f]
+		}
+		return total >= need && holdsRequired(picked, required)
+	}
+	for i, f := range hand {
+		if completes(append(slices.Clone(picked), f), hand[i+1:], required, seats-1, need) {
+			return true
+		}
+	}
+	return false
+}
+
+func holdsRequired(factions, required []matchpb.FactionType) bool {
+	return len(required) == 0 || slices.ContainsFunc(factions, func(f matchpb.FactionType) bool {
+		return slices.Contains(required, f)
+	})
+}
+
+type Pick struct {
+	Seat    int                 `json:"seat"`
+	Faction matchpb.FactionType `json:"faction"`
+	// Auto is set for picks made for a seat that ran out of time.
+	Auto bool `json:"auto"`
+}
+
+// Draft is the state of a draft. It doesn't keep time itself, callers pass
+// the current time and call Expire once the deadline passes.
+type Draft struct {
+	seats    []string
+	hand     []matchpb.FactionType
+	required []matchpb.FactionType
+	picks    []Pick
+	timeout  time.Duration
+	deadline time.Time
+}
+
+// New starts a draft of hand between the seats, named in seat order. With a
+// timeout each seat has that long to pick.
+func New(seats []string, hand []matchpb.FactionType, timeout time.Duration, now time.Time) (*Draft, error) {
+	if len(seats) == 0 {
+		return nil, errors.New("a draft needs at least one seat")
+	}
+	if len(hand) <= len(seats) {
+		return nil, fmt.Errorf("a hand of %d factions is too small for %d seats", len(hand), len(seats))
+	}
+	d := &Draft{seats: slices.Clone(seats), hand: slices.Clone(hand), timeout: timeout}
+	d.restart(now)
+	return d, nil
+}
+
+// Require makes the draft end with at least one of the factions picked. The
+// hand should hold one, see DealRequiring.
+func (d *Draft) Require(factions []matchpb.FactionType) {
+	d.required = slices.Clone(factions)
+}
+
+func (d *Draft) restart(now time.Time) {
+	d.deadline = time.Time{}
+	if d.timeout > 0 && !d.Done() {
+		d.deadline = now.Add(d.timeout)
+	}
+}
+
+func (d *Draft) Done() bool {
+	return len(d.picks) == len(d.seats)
+}
+
+// Turn is the seat picking next, -1 once the draft is over.
+func (d *Draft) Turn() int {
+	if d.Done() {
+		return -1
+	}
+	return len(d.seats) - 1 - len(d.picks)
+}
+
+// Deadline is when the seat whose turn it is runs out of time, zero without
+// a timeout or once the draft is over.
+func (d *Draft) Deadline() time.Time {
+	return d.deadline
+}
+
+func (d *Draft) Seats() []string {
+	return slices.Clone(d.seats)
+}
+
+// Hand is the factions still to be picked.
+func (d *Draft) Hand() []matchpb.FactionType {
+	return slices.Clone(d.hand)
+}
+
+// Allowed is the part of the hand the seat whose turn it is can pick from,
+// the factions that still let the seats after it reach the total the table
+// needs and take a required faction. Hands that can't do either are drafted
+// without those limits.
+func (d *Draft) Allowed() []matchpb.FactionType {
+	if d.Done() {
+		return nil
+	}
+	picked := []matchpb.FactionType{}
+	for _, pick := range d.picks {
+		picked = append(picked, pick.Faction)
+	}
+	left := len(d.seats) - len(d.picks)
+	need, required := minReach(len(d.seats)), d.required
+	if !completes(picked, d.hand, required, left, need) {
+		need = 0
+	}
+	if !completes(picked, d.hand, required, left, need) {
+		required = nil
+	}
+	allowed := []matchpb.FactionType{}
+	for i, f := range d.hand {
+		rest := slices.Delete(slices.Clone(d.hand), i, i+1)
+		if completes(append(slices.Clone(picked), f), rest, required, left-1, need) {
+			allowed = append(allowed, f)
+		}
+	}
+	return allowed
+}
+
+// Picks are the picks made so far, in the order they were made.
+func (d *Draft) Picks() []Pick {
+	return slices.Clone(d.picks)
+}
+
+// Pick takes faction out of the hand for seat.
+func (d *Draft) Pick(seat int, faction matchpb.FactionType, now time.Time) error {
+	if err := d.take(seat, faction, false); err != nil {
+		return err
+	}
+	d.restart(now)
+	return nil
+}
+
+func (d *Draft) take(seat int, faction matchpb.FactionType, auto bool) error {
+	if d.Done() {
+		return ErrDone
+	}
+	if seat != d.Turn() {
+		return fmt.Errorf("%w: seat %d picks next", ErrNotYourTurn, d.Turn())
+	}
+	i := slices.Index(d.hand, faction)
+	if i < 0 {
+		return fmt.Errorf("%w: %s", ErrNotInHand, faction)
+	}
+	if !slices.Contains(d.Allowed(), faction) {
+		return fmt.Errorf("%w: %s", ErrNotAllowed, faction)
+	}
+	d.hand = slices.Delete(d.hand, i, i+1)
+	d.picks = append(d.picks, Pick{Seat: seat, Faction: faction, Auto: auto})
+	return nil
+}
+
+// Undo puts the last pick back in the hand, giving its seat a fresh timeout.
+func (d *Draft) Undo(now time.Time) error {
+	if len(d.picks) == 0 {
+		return ErrNothingToUndo
+	}
+	last := d.picks[len(d.picks)-1]
+	d.picks = d.picks[:len(d.picks)-1]
+	d.hand = append(d.hand, last.Faction)
+	d.restart(now)
+	return nil
+}
+
+// Expire picks the first faction it can from the hand for every seat whose
+// time ran out by now, and returns how many picks it made. Each seat after
+// one that ran out gets the timeout from the missed deadline.
+func (d *Draft) Expire(now time.Time) int {
+	n := 0
+	for !d.Done() && !d.deadline.IsZero() && !now.Before(d.deadline) {
+		allowed := d.Allowed()
+		if len(allowed) == 0 {
+			break
+		}
+		// The turn and faction are known to be valid here.
+		_ = d.take(d.Turn(), allowed[0], true)
+		d.deadline = d.deadline.Add(d.timeout)
+		n++
+	}
+	if d.Done() {
+		d.deadline = time.Time{}
+	}
+	return n
+}
+
+// Result is the faction each seat picked, in seat order.
+func (d *Draft) Result() ([]matchpb.FactionType, error) {
+	if !d.Done() {
+		return nil, fmt.Errorf("the draft is still on, seat %d picks next", d.Turn())
+	}
+	result := make([]matchpb.FactionType, len(d.seats))
+	for _, pick := range d.picks {
+		result[pick.Seat] = pick.Faction
+	}
+	return result, nil
+}
@@ -0,0 +1,213 @@
+package draft
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"LegacyRoot/matchpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	marquise    = matchpb.FactionType_MARQUISE
+	eyrie       = matchpb.FactionType_EYRIE
+	alliance    = matchpb.FactionType_ALLIANCE
+	vagabond    = matchpb.FactionType_VAGABOND
+	lizard      = matchpb.FactionType_LIZARD
+	corvid      = matchpb.FactionType_CORVID
+	underground = matchpb.FactionType_UNDERGROUND
+)
+
+var start = time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)
+
+func allFactions() []matchpb.FactionType {
+	factions := []matchpb.FactionType{}
+	for f := range Reach {
+		factions = append(factions, f)
+	}
+	return factions
+}
+
+func TestDealReach(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for players := 1; players <= 6; players++ {
+		for range 50 {
+			hand, err := Deal(rng, players, allFactions(), []matchpb.FactionType{eyrie})
+			require.NoError(t, err)
+			require.Len(t, hand, players+1)
+			assert.NotContains(t, hand, eyrie)
+			total := 0
+			for _, f := range hand {
+				total += Reach[f]
+			}
+			assert.GreaterOrEqual(t, total, minReach(players), "%v", hand)
+		}
+	}
+}
+
+func TestDealSameSeed(t *testing.T) {
+	a, err := Deal(rand.New(rand.NewSource(7)), 3, allFactions(), nil)
+	require.NoError(t, err)
+	b, err := Deal(rand.New(rand.NewSource(7)), 3, allFactions(), nil)
+	require.NoError(t, err)
+	assert.Equal(t, a, b)
+}
+
+func TestDealNoHand(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	_, err := Deal(rng, 2, []matchpb.FactionType{alliance, lizard}, nil)
+	assert.ErrorIs(t, err, ErrNoHand)
+	// Three weak factions can't reach 17 between them.
+	_, err = Deal(rng, 2, []matchpb.FactionType{alliance, lizard, corvid}, nil)
+	assert.ErrorIs(t, err, ErrNoHand)
+	_, err = Deal(rng, 1, []matchpb.FactionType{alliance, lizard}, []matchpb.FactionType{lizard})
+	assert.ErrorIs(t, err, ErrNoHand)
+}
+
+func TestDraftReverseSeatOrder(t *testing.T) {
+	d, err := New([]string{"ana", "bo", "cy"}, []matchpb.FactionType{marquise, eyrie, alliance, vagabond}, 0, start)
+	require.NoError(t, err)
+	assert.True(t, d.Deadline().IsZero())
+
+	assert.Equal(t, 2, d.Turn())
+	assert.ErrorIs(t, d.Pick(0, marquise, start), ErrNotYourTurn)
+	require.NoError(t, d.Pick(2, marquise, start))
+	assert.ErrorIs(t, d.Pick(1, marquise, start), ErrNotInHand)
+	assert.ErrorIs(t, d.Pick(1, underground, start), ErrNotInHand)
+	require.NoError(t, d.Pick(1, alliance, start))
+	_, err = d.Result()
+	assert.Error(t, err)
+	require.NoError(t, d.Pick(0, vagabond, start))
+
+	assert.True(t, d.Done())
+	assert.Equal(t, -1, d.Turn())
+	assert.Equal(t, []matchpb.FactionType{eyrie}, d.Hand())
+	assert.ErrorIs(t, d.Pick(0, eyrie, start), ErrDone)
+	result, err := d.Result()
+	require.NoError(t, err)
+	assert.Equal(t, []matchpb.FactionType{vagabond, alliance, marquise}, result)
+}
+
+func TestDraftUndo(t *testing.T) {
+	d, err := New([]string{"ana", "bo"}, []matchpb.FactionType{marquise, eyrie, alliance}, time.Minute, start)
+	require.NoError(t, err)
+	assert.ErrorIs(t, d.Undo(start), ErrNothingToUndo)
+
+	require.NoError(t, d.Pick(1, eyrie, start.Add(10*time.Second)))
+	require.NoError(t, d.Pick(0, marquise, start.Add(20*time.Second)))
+	assert.True(t, d.Done())
+	assert.True(t, d.Deadline().IsZero())
+
+	// Undoing reopens the last seat's turn with a fresh timeout.
+	require.NoError(t, d.Undo(start.Add(30*time.Second)))
+	assert.False(t, d.Done())
+	assert.Equal(t, 0, d.Turn())
+	assert.Equal(t, start.Add(90*time.Second), d.Deadline())
+	assert.ElementsMatch(t, []matchpb.FactionType{alliance, marquise}, d.Hand())
+
+	require.NoError(t, d.Undo(start.Add(40*time.Second)))
+	assert.Equal(t, 1, d.Turn())
+	assert.Empty(t, d.Picks())
+	require.NoError(t, d.Pick(1, marquise, start.Add(50*time.Second)))
+	require.NoError(t, d.Pick(0, eyrie, start.Add(60*time.Second)))
+	result, err := d.Result()
+	require.NoError(t, err)
+	assert.Equal(t, []matchpb.FactionType{eyrie, marquise}, result)
+}
+
+func TestDraftTimeout(t *testing.T) {
+	d, err := New([]string{"ana", "bo", "cy"}, []matchpb.FactionType{marquise, eyrie, alliance, vagabond}, time.Minute, start)
+	require.NoError(t, err)
+	assert.Equal(t, start.Add(time.Minute), d.Deadline())
+
+	assert.Zero(t, d.Expire(start.Add(59*time.Second)))
+	require.NoError(t, d.Pick(2, eyrie, start.Add(30*time.Second)))
+	assert.Equal(t, start.Add(90*time.Second), d.Deadline())
+
+	// Seat 1 runs out of time, and seat 0 gets a minute from there.
+	assert.Equal(t, 1, d.Expire(start.Add(100*time.Second)))
+	assert.Equal(t, Pick{Seat: 1, Faction: marquise, Auto: true}, d.Picks()[1])
+	assert.Equal(t, start.Add(150*time.Second), d.Deadline())
+
+	// An auto pick can be undone like any other.
+	require.NoError(t, d.Undo(start.Add(110*time.Second)))
+	assert.Equal(t, 1, d.Turn())
+
+	// Both remaining seats ran out, and seat 0 is left the pick that keeps
+	// the table's reach.
+	assert.Equal(t, 2, d.Expire(start.Add(time.Hour)))
+	assert.True(t, d.Done())
+	assert.True(t, d.Deadline().IsZero())
+	assert.Zero(t, d.Expire(start.Add(2*time.Hour)))
+	result, err := d.Result()
+	require.NoError(t, err)
+	assert.Equal(t, []matchpb.FactionType{marquise, alliance, eyrie}, result)
+}
+
+func TestNewDraftInvalid(t *testing.T) {
+	_, err := New(nil, []matchpb.FactionType{marquise}, 0, start)
+	assert.Error(t, err)
+	_, err = New([]string{"ana", "bo"}, []matchpb.FactionType{marquise, eyrie}, 0, start)
+	assert.Error(t, err)
+}
+
+func TestDealRequiring(t *testing.T) {
+	rng := rand.New(rand.NewSource(3))
+	militant := []matchpb.FactionType{marquise, eyrie, underground}
+	for range 50 {
+		hand, err := DealRequiring(rng, 2, allFactions(), nil, militant)
+		require.NoError(t, err)
+		assert.True(t, holdsRequired(hand, militant), "%v", hand)
+	}
+	_, err := DealRequiring(rng, 1, []matchpb.FactionType{alliance, lizard, corvid}, nil, militant)
+	assert.ErrorIs(t, err, ErrNoHand)
+}
+
+func TestDraftRequire(t *testing.T) {
+	d, err := New([]string{"ana", "bo", "cy"}, []matchpb.FactionType{alliance, vagabond, marquise, corvid}, time.Minute, start)
+	require.NoError(t, err)
+	d.Require([]matchpb.FactionType{marquise, eyrie})
+
+	require.NoError(t, d.Pick(2, alliance, start))
+	require.NoError(t, d.Pick(1, vagabond, start))
+	// Nobody took a required faction, so the last seat has to.
+	assert.Equal(t, []matchpb.FactionType{marquise}, d.Allowed())
+	assert.ErrorIs(t, d.Pick(0, corvid, start), ErrNotAllowed)
+
+	require.NoError(t, d.Undo(start))
+	require.NoError(t, d.Pick(1, marquise, start))
+	// Corvid would leave the table short of 18.
+	assert.Equal(t, []matchpb.FactionType{vagabond}, d.Allowed())
+
+	require.NoError(t, d.Undo(start))
+	assert.ErrorIs(t, d.Pick(1, corvid, start), ErrNotAllowed)
+	require.NoError(t, d.Pick(1, vagabond, start))
+	assert.Equal(t, 1, d.Expire(start.Add(time.Hour)))
+	result, err := d.Result()
+	require.NoError(t, err)
+	assert.Equal(t, []matchpb.FactionType{marquise, vagabond, alliance}, result)
+}
+
+func TestDraftReach(t *testing.T) {
+	// The hand reaches 19, but Lizard and either other faction falls short.
+	d, err := New([]string{"ana", "bo"}, []matchpb.FactionType{marquise, eyrie, lizard}, time.Minute, start)
+	require.NoError(t, err)
+	assert.Equal(t, []matchpb.FactionType{marquise, eyrie}, d.Allowed())
+	assert.ErrorIs(t, d.Pick(1, lizard, start), ErrNotAllowed)
+
+	require.NoError(t, d.Pick(1, eyrie, start))
+	assert.Equal(t, []matchpb.FactionType{marquise}, d.Allowed())
+	assert.ErrorIs(t, d.Pick(0, lizard, start), ErrNotAllowed)
+	assert.Equal(t, 1, d.Expire(start.Add(time.Hour)))
+	result, err := d.Result()
+	require.NoError(t, err)
+	assert.Equal(t, []matchpb.FactionType{marquise, eyrie}, result)
+
+	// A hand that can't reach the total at all is drafted freely.
+	d, err = New([]string{"ana", "bo"}, []matchpb.FactionType{alliance, lizard, corvid}, 0, start)
+	require.NoError(t, err)
+	assert.Equal(t, []matchpb.FactionType{alliance, lizard, corvid}, d.Allowed())
+}
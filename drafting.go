@@ -0,0 +1,311 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"LegacyRoot/board"
+	"LegacyRoot/draft"
+	"LegacyRoot/matchpb"
+
+	"github.com/labstack/echo"
+)
+
+// DraftState is what clients see of a session's draft. Factions are named
+// the way the pick endpoint takes them, and Allowed is the part of the hand
+// the seat whose turn it is can pick.
+type DraftState struct {
+	Seats    []string    `json:"seats"`
+	Hand     []string    `json:"hand"`
+	Allowed  []string    `json:"allowed"`
+	Picks    []DraftPick `json:"picks"`
+	Turn     int         `json:"turn"`
+	Deadline time.Time   `json:"deadline"`
+}
+
+type DraftPick struct {
+	Seat    int    `json:"seat"`
+	Faction string `json:"faction"`
+	Auto    bool   `json:"auto"`
+}
+
+func factionParamName(f matchpb.FactionType) string {
+	return strings.ToLower(f.String())
+}
+
+// draftFactionName is the full name of a faction named by factionParamName.
+func draftFactionName(name string) string {
+	return getFactionName(matchpb.FactionType_value[strings.ToUpper(name)])
+}
+
+func draftPickOf(state *DraftState, seat int) (DraftPick, bool) {
+	for _, pick := range state.Picks {
+		if pick.Seat == seat {
+			return pick, true
+		}
+	}
+	return DraftPick{}, false
+}
+
+// draftState returns the session's draft, nil without one. s.mu must be held.
+func (s *Session) draftState() *DraftState {
+	if s.draft == nil {
+		return nil
+	}
+	state := &DraftState{
+		Seats:    s.draft.Seats(),
+		Hand:     []string{},
+		Allowed:  []string{},
+		Picks:    []DraftPick{},
+		Turn:     s.draft.Turn(),
+		Deadline: s.draft.Deadline(),
+	}
+	for _, f := range s.draft.Hand() {
+		state.Hand = append(state.Hand, factionParamName(f))
+	}
+	for _, f := range s.draft.Allowed() {
+		state.Allowed = append(state.Allowed, factionParamName(f))
+	}
+	for _, pick := range s.draft.Picks() {
+		state.Picks = append(state.Picks, DraftPick{Seat: pick.Seat, Faction: factionParamName(pick.Faction), Auto: pick.Auto})
+	}
+	return state
+}
+
+func (s *Session) Draft() *DraftState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.draftState()
+}
+
+// StartDraft deals a hand for the seats to draft their factions from,
+// leaving out the factions the match's bots and hirelings play. ADSET matches
+// have to end up with a militant faction. It replaces any draft already
+// going.
+func (s *Session) StartDraft(ctx context.Context, seats []string, timeout time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.record == nil {
+		return echo.NewHTTPError(http.StatusConflict, "the session has no match to draft for yet")
+	}
+	exclude := []matchpb.FactionType{}
+	for _, bot := range s.record.GetMatch().GetBots() {
+		exclude = append(exclude, bot.GetType())
+	}
+	for _, hireling := range s.record.GetMatch().GetHirelings() {
+		exclude = append(exclude, hireling.GetType())
+	}
+	pool := []matchpb.FactionType{}
+	for _, f := range sortedKeys(FactionNames) {
+		pool = append(pool, matchpb.FactionType(f))
+	}
+
+	var required []matchpb.FactionType
+	if s.cfg.Setup == AdsetSetup {
+		required = factionsOfClass(Militant)
+	}
+
+	rng := rand.New(rand.NewSource(newSeed()))
+	hand, err := draft.DealRequiring(rng, len(seats), pool, exclude, required)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	}
+	d, err := draft.New(seats, hand, timeout, s.clock.Now())
+	if err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	d.Require(required)
+	s.stopDraft()
+	s.draft = d
+	s.seatVoters = map[int]string{}
+	return s.draftChanged(ctx)
+}
+
+// PickFaction picks faction for seat, which has to be the seat whose turn it
+// is. The host picks for any seat. A voter's first pick claims the seat, and
+// from then on only they pick for it and for no other.
+func (s *Session) PickFaction(ctx context.Context, seat int, faction matchpb.FactionType, voter string, host bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.draft == nil {
+		return echo.NewHTTPError(http.StatusConflict, "the session isn't drafting")
+	}
+	if !host {
+		if !s.voters[voter] {
+			return echo.NewHTTPError(http.StatusForbidden, "join the session to pick")
+		}
+		for claimed, v := range s.seatVoters {
+			if claimed == seat && v != voter {
+				return echo.NewHTTPError(http.StatusForbidden, "somebody else picks for that seat")
+			}
+			if claimed != seat && v == voter {
+				return echo.NewHTTPError(http.StatusForbidden, "you already pick for another seat")
+			}
+		}
+	}
+	if err := s.draft.Pick(seat, faction, s.clock.Now()); err != nil {
+		return draftError(err)
+	}
+	if !host {
+		s.seatVoters[seat] = voter
+	}
+	return s.draftChanged(ctx)
+}
+
+// UndoPick takes back the last pick of the session's draft.
+func (s *Session) UndoPick(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.draft == nil {
+		return echo.NewHTTPError(http.StatusConflict, "the session isn't drafting")
+	}
+	if err := s.draft.Undo(s.clock.Now()); err != nil {
+		return draftError(err)
+	}
+	return s.draftChanged(ctx)
+}
+
+func draftError(err error) error {
+	if errors.Is(err, draft.ErrNotInHand) || errors.Is(err, draft.ErrNotAllowed) {
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return echo.NewHTTPError(http.StatusConflict, err.Error())
+}
+
+// draftChanged stores the drafted factions as the match's players once the
+// draft is over, seating them in draft order ahead of the bots, and sends
+// the new state to every client. Until then the seat whose turn it is gets
+// timed. s.mu must be held.
+func (s *Session) draftChanged(ctx context.Context) error {
+	if s.draftTimer != nil {
+		s.draftTimer.Stop()
+		s.draftTimer = nil
+	}
+	if s.draft.Done() {
+		factions, err := s.draft.Result()
+		if err != nil {
+			return err
+		}
+		env, err := s.history.ModifyEnvelope(s.record.GetId(), func(env *matchpb.Envelope) error {
+			// The seed doesn't generate the drafted players.
+			env.Seed = 0
+			record := env.GetRecord()
+			seats := tableSize(record.GetMatch())
+			record.Match.Players = nil
+			for _, f := range factions {
+				record.Match.Players = append(record.Match.Players, NewFaction(int32(f)))
+			}
+			// The drafted table can be bigger than the one the hirelings
+			// were demoted for.
+			if demotedHirelings(tableSize(record.Match)) != demotedHirelings(seats) {
+				demoteHirelings(rand.New(rand.NewSource(newSeed())), record.Match.GetHirelings(), tableSize(record.Match))
+			}
+			seatInOrder(record.Match, s.cfg.SetupOrder)
+			record.Match.Homes = board.Homes(record.Match)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		s.record = env.GetRecord()
+	} else if deadline := s.draft.Deadline(); !deadline.IsZero() {
+		d := s.draft
+		s.draftTimer = s.clock.AfterFunc(deadline.Sub(s.clock.Now()), func() {
+			s.mu.Lock()
+			defer s.mu.Unlock()
+			if s.draft == d && d.Expire(s.clock.Now()) > 0 {
+				// Nobody is waiting on a timed out pick to report errors to.
+				_ = s.draftChanged(context.Background())
+			}
+		})
+	}
+	return s.broadcast(ctx)
+}
+
+// stopDraft calls off the session's draft. s.mu must be held.
+func (s *Session) stopDraft() {
+	if s.draftTimer != nil {
+		s.draftTimer.Stop()
+		s.draftTimer = nil
+	}
+	s.draft = nil
+}
+
+// formSeats reads the seats of a draft, named in seat order and separated by
+// commas.
+func formSeats(c echo.Context) ([]string, error) {
+	seats := []string{}
+	for _, seat := range strings.Split(c.FormValue("seats"), ",") {
+		if seat = strings.TrimSpace(seat); seat != "" {
+			seats = append(seats, seat)
+		}
+	}
+	if len(seats) == 0 {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "missing seats")
+	}
+	return seats, nil
+}
+
+func startDraftHandler(sessions *Sessions) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		session, err := hostSession(c, sessions)
+		if err != nil {
+			return err
+		}
+		seats, err := formSeats(c)
+		if err != nil {
+			return err
+		}
+		var timeout time.Duration
+		if s := c.FormValue("draft-timeout"); s != "" {
+			if timeout, err = time.ParseDuration(s); err != nil {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid draft-timeout: %v", err))
+			}
+		}
+		if err := session.StartDraft(c.Request().Context(), seats, timeout); err != nil {
+			return err
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func pickHandler(sessions *Sessions) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		session, err := sessionParam(c, sessions)
+		if err != nil {
+			return err
+		}
+		seat, err := strconv.Atoi(c.FormValue("seat"))
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid seat: %v", err))
+		}
+		faction, ok := matchpb.FactionType_value[strings.ToUpper(c.FormValue("faction"))]
+		if !ok {
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("unknown faction %q", c.FormValue("faction")))
+		}
+		host := session.IsHost(hostToken(c, session))
+		if err := session.PickFaction(c.Request().Context(), seat, matchpb.FactionType(faction), voterToken(c, session), host); err != nil {
+			return err
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func undoPickHandler(sessions *Sessions) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		session, err := hostSession(c, sessions)
+		if err != nil {
+			return err
+		}
+		if err := session.UndoPick(c.Request().Context()); err != nil {
+			return err
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"LegacyRoot/matchpb"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSessionDraft(t *testing.T) {
+	ctx := context.Background()
+	session, clock, record := votingSession(t, defaultVoteRules(), 1)
+	events := session.subscribe(0)
+	defer session.unsubscribe(events)
+	<-events
+
+	session.Record().Match.Hirelings = []*matchpb.Hireling{
+		{Type: matchpb.FactionType_BANDITS, Name: Hirelings[Bandits][0], Threshold: 4},
+		{Type: matchpb.FactionType_PROTECTOR, Name: Hirelings[Protector][0], Threshold: 8},
+	}
+	require.NoError(t, session.StartDraft(ctx, []string{"ana", "bo", "cy"}, time.Minute))
+	state := session.Draft()
+	require.Len(t, state.Hand, 4)
+	assert.Equal(t, 2, state.Turn)
+	assert.Equal(t, clock.Now().Add(time.Minute), state.Deadline)
+	event := <-events
+	assert.Equal(t, state, event.Draft)
+	assert.Contains(t, event.DraftHTML, "cy picks now until 8:01PM")
+
+	assert.Equal(t, http.StatusConflict, httpCode(session.PickFaction(ctx, 0, matchpb.FactionType(0), "", true)))
+	require.NotEmpty(t, state.Allowed)
+	require.NoError(t, session.PickFaction(ctx, 2, faction(state.Allowed[0]), "", true))
+	require.NoError(t, session.UndoPick(ctx))
+	require.NoError(t, session.PickFaction(ctx, 2, faction(state.Allowed[len(state.Allowed)-1]), "", true))
+
+	// Seat 1 runs out of time and gets the first faction it was allowed.
+	clock.Advance(time.Minute)
+	state = session.Draft()
+	require.Len(t, state.Picks, 2)
+	assert.Equal(t, DraftPick{Seat: 1, Faction: state.Picks[1].Faction, Auto: true}, state.Picks[1])
+	assert.Equal(t, 0, state.Turn)
+	assert.Len(t, session.Record().GetMatch().GetPlayers(), 1)
+
+	require.NoError(t, session.PickFaction(ctx, 0, faction(state.Allowed[0]), "", true))
+	state = session.Draft()
+	assert.Equal(t, -1, state.Turn)
+	players := session.Record().GetMatch().GetPlayers()
+	require.Len(t, players, 3)
+	// Three seats demote one of the hirelings.
+	assert.Equal(t, 1, countDemoted(session.Record().GetMatch().GetHirelings()))
+	for _, pick := range state.Picks {
+		assert.Equal(t, faction(pick.Faction), players[pick.Seat].GetType())
+	}
+	assert.Equal(t, record.GetId(), session.Record().GetId())
+	stored, _ := session.history.Get(record.GetId())
+	assert.Len(t, stored.GetMatch().GetPlayers(), 3)
+	assert.Empty(t, Validate(stored.GetMatch()))
+
+	// A new match calls off the draft.
+	require.NoError(t, session.StartDraft(ctx, []string{"ana"}, time.Minute))
+	cfg := defaultMatchCfg()
+	require.NoError(t, session.Publish(ctx, record, &cfg))
+	assert.Nil(t, session.Draft())
+	clock.Advance(time.Hour)
+	assert.Nil(t, session.Draft())
+}
+
+func TestSessionDraftLeavesOutBotsAndHirelings(t *testing.T) {
+	ctx := context.Background()
+	session, _, _ := votingSession(t, defaultVoteRules(), 1)
+	record := session.Record()
+	record.Match.Bots = []*matchpb.Bot{{Type: matchpb.FactionType_MARQUISE}}
+	record.Match.Hirelings = []*matchpb.Hireling{{Type: matchpb.FactionType_EYRIE}}
+	for range 20 {
+		require.NoError(t, session.StartDraft(ctx, []string{"ana", "bo"}, 0))
+		hand := session.Draft().Hand
+		assert.NotContains(t, hand, "marquise")
+		assert.NotContains(t, hand, "eyrie")
+	}
+}
+
+func faction(name string) matchpb.FactionType {
+	return matchpb.FactionType(matchpb.FactionType_value[strings.ToUpper(name)])
+}
+
+func httpCode(err error) int {
+	if he, ok := err.(*echo.HTTPError); ok {
+		return he.Code
+	}
+	return 0
+}
+
+func TestDraftEndpoints(t *testing.T) {
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.pb"))
+	require.NoError(t, err)
+	e := newServer(history, &Campaigns{})
+	post := func(target string, form url.Values, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		req.Header.Set("X-Host-Token", token)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := post("/api/sessions", nil, "")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	created := createSessionResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	base := "/api/sessions/" + created.Id
+
+	seats := url.Values{"seats": {"ana, bo"}}
+	assert.Equal(t, http.StatusConflict, post(base+"/draft", seats, created.HostToken).Code)
+	require.Equal(t, http.StatusNoContent, post(base+"/generate", url.Values{"hirelings": {"false"}}, created.HostToken).Code)
+	assert.Equal(t, http.StatusForbidden, post(base+"/draft", seats, "").Code)
+	assert.Equal(t, http.StatusBadRequest, post(base+"/draft", url.Values{"seats": {" , "}}, created.HostToken).Code)
+	assert.Equal(t, http.StatusBadRequest, post(base+"/draft", url.Values{"seats": {"ana"}, "draft-timeout": {"soon"}}, created.HostToken).Code)
+	assert.Equal(t, http.StatusConflict, post(base+"/draft/pick", url.Values{"seat": {"1"}, "faction": {"marquise"}}, "").Code)
+	require.Equal(t, http.StatusNoContent, post(base+"/draft", seats, created.HostToken).Code)
+
+	join := func() string {
+		rec := post(base+"/voters", nil, created.HostToken)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		joined := joinResponse{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &joined))
+		return joined.VoterToken
+	}
+	ana, bo := join(), join()
+	pick := func(seat, faction, voter string) int {
+		req := httptest.NewRequest(http.MethodPost, base+"/draft/pick", strings.NewReader(url.Values{"seat": {seat}, "faction": {faction}}.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		req.Header.Set("X-Voter-Token", voter)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+	// pickAny picks the first faction the seat is allowed.
+	pickAny := func(seat, voter string) int {
+		for _, f := range sortedKeys(FactionNames) {
+			if code := pick(seat, factionParamName(matchpb.FactionType(f)), voter); code != http.StatusBadRequest {
+				return code
+			}
+		}
+		return http.StatusBadRequest
+	}
+
+	assert.Equal(t, http.StatusBadRequest, pick("1", "otters", bo))
+	assert.Equal(t, http.StatusBadRequest, pick("one", "marquise", bo))
+	assert.Equal(t, http.StatusForbidden, pickAny("1", ""))
+	assert.Equal(t, http.StatusForbidden, pickAny("1", "made-up"))
+	assert.Equal(t, http.StatusConflict, pick("0", "marquise", ana))
+	// Factions outside the hand are turned down until one in it is picked,
+	// then it is seat 0's turn.
+	picked := 0
+	for _, f := range sortedKeys(FactionNames) {
+		code := pick("1", factionParamName(matchpb.FactionType(f)), bo)
+		switch {
+		case code == http.StatusNoContent:
+			picked++
+		case picked == 0:
+			assert.Equal(t, http.StatusBadRequest, code)
+		default:
+			assert.Equal(t, http.StatusConflict, code)
+		}
+	}
+	assert.Equal(t, 1, picked)
+	assert.Equal(t, http.StatusForbidden, post(base+"/draft/undo", nil, "").Code)
+	assert.Equal(t, http.StatusNoContent, post(base+"/draft/undo", nil, created.HostToken).Code)
+	assert.Equal(t, http.StatusConflict, post(base+"/draft/undo", nil, created.HostToken).Code)
+
+	// Bo picked for seat 1, so ana can't, and bo can't pick for seat 0.
+	assert.Equal(t, http.StatusForbidden, pickAny("1", ana))
+	require.Equal(t, http.StatusNoContent, pickAny("1", bo))
+	assert.Equal(t, http.StatusForbidden, pickAny("0", bo))
+	require.Equal(t, http.StatusNoContent, pickAny("0", ana))
+
+	// The host picks for any seat.
+	require.Equal(t, http.StatusNoContent, post(base+"/draft/undo", nil, created.HostToken).Code)
+	hostPicked := false
+	for _, f := range sortedKeys(FactionNames) {
+		if post(base+"/draft/pick", url.Values{"seat": {"0"}, "faction": {factionParamName(matchpb.FactionType(f))}}, created.HostToken).Code == http.StatusNoContent {
+			hostPicked = true
+			break
+		}
+	}
+	assert.True(t, hostPicked)
+}
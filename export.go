@@ -0,0 +1,382 @@
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"LegacyRoot/board"
+	"LegacyRoot/matchpb"
+	"LegacyRoot/render"
+
+	"github.com/labstack/echo"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Columns of the match export. New columns go at the end so spreadsheets
+// pasted from older exports keep lining up.
+var exportColumns = []string{
+	"id",
+	"date",
+	"seats",
+	"players",
+	"bots",
+	"bot_difficulties",
+	"bot_traits",
+	"hirelings",
+	"map",
+	"landmarks",
+	"results",
+	"winner",
+	"deck",
+	"seat_order",
+	"first_seat",
+	"setup_order",
+}
+
+// Columns appended after the first exports, which can do without them.
+var optionalExportColumns = []string{"deck", "seat_order", "first_seat", "setup_order"}
+
+// Separates the entries of a list cell.
+const listSeparator = "; "
+
+var (
+	hirelingCell = regexp.MustCompile(`^at (\d+) VP: (.+) \((promoted|demoted)\)$`)
+	resultCell   = regexp.MustCompile(`^(.+): (.+) (-?\d+)$`)
+)
+
+func joinCell[T any](items []T, cell func(T) string) string {
+	cells := []string{}
+	for _, item := range items {
+		cells = append(cells, cell(item))
+	}
+	return strings.Join(cells, listSeparator)
+}
+
+func splitCell(cell string) []string {
+	if strings.TrimSpace(cell) == "" {
+		return nil
+	}
+	return strings.Split(cell, listSeparator)
+}
+
+func matchRow(record *matchpb.MatchRecord) []string {
+	match := record.GetMatch()
+	winners := []string{}
+	for _, result := range record.GetResults() {
+		if result.GetWinner() {
+			winners = append(winners, result.GetPlayer())
+		}
+	}
+	return []string{
+		record.GetId(),
+		record.GetCreatedAt().AsTime().Format(time.RFC3339),
+		strconv.Itoa(len(match.GetPlayers()) + len(match.GetBots())),
+		joinCell(match.GetPlayers(), (*matchpb.Faction).GetName),
+		joinCell(match.GetBots(), (*matchpb.Bot).GetName),
+		joinCell(match.GetBots(), func(b *matchpb.Bot) string { return render.DifficultyNames[b.GetDifficulty()] }),
+		joinCell(match.GetBots(), func(b *matchpb.Bot) string { return strings.Join(b.GetTraits(), ", ") }),
+		joinCell(match.GetHirelings(), render.HirelingLabel),
+		match.GetMap().GetName(),
+		joinCell(match.GetLandmarks(), (*matchpb.Landmark).GetName),
+		joinCell(record.GetResults(), func(r *matchpb.Result) string {
+			return fmt.Sprintf("%s: %s %d", r.GetPlayer(), getFactionName(int32(r.GetFaction())), r.GetScore())
+		}),
+		strings.Join(winners, listSeparator),
+		render.DeckNames[match.GetDeck()],
+		joinCell(match.GetSeats(), seatCell),
+		firstSeatCell(match),
+		joinCell(match.GetSeats(), func(s *matchpb.Seat) string { return strconv.Itoa(int(s.GetSetupOrder())) }),
+	}
+}
+
+// seatCell names a seat by its faction, bots being marked as such.
+func seatCell(seat *matchpb.Seat) string {
+	if seat.GetBot() {
+		return getFactionName(int32(seat.GetFaction())) + " (bot)"
+	}
+	return getFactionName(int32(seat.GetFaction()))
+}
+
+func firstSeatCell(match *matchpb.Match) string {
+	if match.GetFirstSeat() == 0 {
+		return ""
+	}
+	return strconv.Itoa(int(match.GetFirstSeat()))
+}
+
+// writeMatchesCSV writes the records one row each, comma separated for CSV
+// or tab separated for pasting into a spreadsheet.
+func writeMatchesCSV(w io.Writer, records []*matchpb.MatchRecord, comma rune) error {
+	cw := csv.NewWriter(w)
+	cw.Comma = comma
+	if err := cw.Write(exportColumns); err != nil {
+		return err
+	}
+	for _, record := range records {
+		if err := cw.Write(matchRow(record)); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// readMatchesCSV reads records back from an export.
+func readMatchesCSV(r io.Reader, comma rune) ([]*matchpb.MatchRecord, error) {
+	cr := csv.NewReader(r)
+	cr.Comma = comma
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read export: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	columns := map[string]int{}
+	for i, name := range rows[0] {
+		columns[name] = i
+	}
+	for _, name := range exportColumns {
+		if _, ok := columns[name]; !ok && !slices.Contains(optionalExportColumns, name) {
+			return nil, fmt.Errorf("export is missing the %s column", name)
+		}
+	}
+
+	records := []*matchpb.MatchRecord{}
+	for i, row := range rows[1:] {
+		cell := func(name string) string {
+			if i, ok := columns[name]; ok {
+				return row[i]
+			}
+			return ""
+		}
+		record, err := parseMatchRow(cell)
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+2, err)
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
+
+func parseMatchRow(cell func(string) string) (*matchpb.MatchRecord, error) {
+	createdAt, err := time.Parse(time.RFC3339, cell("date"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid date %q: %w", cell("date"), err)
+	}
+	match := &matchpb.Match{}
+
+	for _, name := range splitCell(cell("players")) {
+		faction := FactionId(name)
+		if faction == None {
+			return nil, fmt.Errorf("unknown faction %q", name)
+		}
+		match.Players = append(match.Players, NewFaction(faction))
+	}
+
+	difficulties, traits := splitCell(cell("bot_difficulties")), strings.Split(cell("bot_traits"), listSeparator)
+	for i, name := range splitCell(cell("bots")) {
+		bot, err := parseBotCell(name)
+		if err != nil {
+			return nil, err
+		}
+		if i < len(difficulties) {
+			for difficulty, name := range render.DifficultyNames {
+				if name == difficulties[i] {
+					bot.Difficulty = difficulty
+				}
+			}
+		}
+		if i < len(traits) && traits[i] != "" {
+			bot.Traits = strings.Split(traits[i], ", ")
+		}
+		match.Bots = append(match.Bots, bot)
+	}
+
+	for _, label := range splitCell(cell("hirelings")) {
+		hireling, err := parseHirelingCell(label)
+		if err != nil {
+			return nil, err
+		}
+		match.Hirelings = append(match.Hirelings, hireling)
+	}
+
+	if name := cell("map"); name != "" {
+		for m, mapName := range MapNames {
+			if mapName == name {
+				match.Map = &matchpb.MapVal{Type: matchpb.MapType(m), Name: name}
+			}
+		}
+		if match.Map == nil {
+			return nil, fmt.Errorf("unknown map %q", name)
+		}
+	}
+
+	for _, name := range splitCell(cell("landmarks")) {
+		landmark := int32(-1)
+		for _, l := range Landmarks {
+			if getLandmarkName(l) == name {
+				landmark = l
+			}
+		}
+		if landmark < 0 {
+			return nil, fmt.Errorf("unknown landmark %q", name)
+		}
+		match.Landmarks = append(match.Landmarks, &matchpb.Landmark{Type: matchpb.LandmarkType(landmark), Name: name})
+	}
+
+	if name := cell("deck"); name != "" {
+		for d, deckName := range render.DeckNames {
+			if deckName == name {
+				match.Deck = d
+			}
+		}
+		if match.Deck == matchpb.Deck_UNKNOWN_DECK {
+			return nil, fmt.Errorf("unknown deck %q", name)
+		}
+	}
+
+	if err := parseSeatCells(match, splitCell(cell("seat_order")), cell("first_seat"), splitCell(cell("setup_order"))); err != nil {
+		return nil, err
+	}
+	// Homes follow from the seats and the map, so they aren't exported.
+	if len(match.Seats) > 0 {
+		match.Homes = board.Homes(match)
+	}
+
+	results, err := parseResultsCell(cell("results"), splitCell(cell("winner")))
+	if err != nil {
+		return nil, err
+	}
+	return &matchpb.MatchRecord{
+		Id:        cell("id"),
+		CreatedAt: timestamppb.New(createdAt),
+		Match:     match,
+		Results:   results,
+	}, nil
+}
+
+func parseSeatCells(match *matchpb.Match, seats []string, first string, setup []string) error {
+	for i, name := range seats {
+		seat := &matchpb.Seat{}
+		if trimmed, ok := strings.CutSuffix(name, " (bot)"); ok {
+			seat.Bot, name = true, trimmed
+		}
+		faction := FactionId(name)
+		if faction == None {
+			return fmt.Errorf("unknown faction %q in seat %d", name, i+1)
+		}
+		seat.Faction = matchpb.FactionType(faction)
+		if i < len(setup) {
+			order, err := strconv.Atoi(setup[i])
+			if err != nil {
+				return fmt.Errorf("invalid setup order %q: %w", setup[i], err)
+			}
+			seat.SetupOrder = int32(order)
+		}
+		match.Seats = append(match.Seats, seat)
+	}
+	if first != "" {
+		n, err := strconv.Atoi(first)
+		if err != nil {
+			return fmt.Errorf("invalid first seat %q: %w", first, err)
+		}
+		match.FirstSeat = int32(n)
+	}
+	return nil
+}
+
+// parseBotCell accepts Clockwork names as well as the faction names older
+// matches used for bots.
+func parseBotCell(name string) (*matchpb.Bot, error) {
+	for clockwork, entry := range BotCatalog {
+		if entry.Name == name {
+			return &matchpb.Bot{Type: matchpb.FactionType(entry.Mirrors), Name: name, Clockwork: clockwork}, nil
+		}
+	}
+	if faction := FactionId(name); faction != None {
+		return &matchpb.Bot{Type: matchpb.FactionType(faction), Name: name, Clockwork: mirroringBot(matchpb.FactionType(faction))}, nil
+	}
+	return nil, fmt.Errorf("unknown bot %q", name)
+}
+
+func parseHirelingCell(label string) (*matchpb.Hireling, error) {
+	parts := hirelingCell.FindStringSubmatch(label)
+	if parts == nil {
+		return nil, fmt.Errorf("hireling %q is not \"at N VP: name (status)\"", label)
+	}
+	threshold, _ := strconv.Atoi(parts[1])
+	status := matchpb.HirelingStatus(matchpb.HirelingStatus_value[strings.ToUpper(parts[3])])
+	for faction, names := range Hirelings {
+		if names[status] == parts[2] {
+			return &matchpb.Hireling{
+				Type:      matchpb.FactionType(faction),
+				Name:      parts[2],
+				Threshold: int32(threshold),
+				Status:    status,
+			}, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown hireling %q", parts[2])
+}
+
+func parseResultsCell(cell string, winners []string) ([]*matchpb.Result, error) {
+	results := []*matchpb.Result{}
+	for _, entry := range splitCell(cell) {
+		parts := resultCell.FindStringSubmatch(entry)
+		if parts == nil {
+			return nil, fmt.Errorf("result %q is not \"player: faction score\"", entry)
+		}
+		faction := FactionId(parts[2])
+		if faction == None {
+			return nil, fmt.Errorf("unknown faction %q", parts[2])
+		}
+		score, _ := strconv.Atoi(parts[3])
+		results = append(results, &matchpb.Result{
+			Player:  parts[1],
+			Faction: matchpb.FactionType(faction),
+			Score:   int32(score),
+			Winner:  slices.Contains(winners, parts[1]),
+		})
+	}
+	return results, nil
+}
+
+// importCSV backfills the history with exported records, skipping ids it
+// already holds. It returns how many records were added.
+func importCSV(r io.Reader, comma rune, history *History) (int, error) {
+	records, err := readMatchesCSV(r, comma)
+	if err != nil {
+		return 0, err
+	}
+	added := 0
+	for _, record := range records {
+		if _, ok := history.Get(record.GetId()); ok {
+			continue
+		}
+		if violations := Validate(record.GetMatch()); len(violations) > 0 {
+			return added, fmt.Errorf("match %s: %w", record.GetId(), ValidationError(violations))
+		}
+		if err := history.Import(record); err != nil {
+			return added, err
+		}
+		added++
+	}
+	return added, nil
+}
+
+func exportHandler(history *History, comma rune, contentType string) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		c.Response().Header().Set(echo.HeaderContentType, contentType)
+		c.Response().WriteHeader(http.StatusOK)
+		return writeMatchesCSV(c.Response(), history.Records(), comma)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"LegacyRoot/matchpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func exportRecords(t *testing.T) []*matchpb.MatchRecord {
+	cfg := defaultMatchCfg()
+	cfg.BotEnemies = 2
+	records := []*matchpb.MatchRecord{}
+	for seed := range int64(3) {
+		match, _, err := generateMatch(&matchpb.Match{}, &cfg, seed)
+		require.NoError(t, err)
+		records = append(records, &matchpb.MatchRecord{
+			Id:        newMatchId(),
+			CreatedAt: timestamppb.New(time.Date(2024, 3, int(seed)+1, 20, 0, 0, 0, time.UTC)),
+			Match:     match,
+		})
+	}
+	records[0].Results = []*matchpb.Result{
+		{Player: "Ana", Faction: matchpb.FactionType(records[0].GetMatch().GetPlayers()[0].GetType()), Score: 30, Winner: true},
+		{Player: "Bo", Faction: matchpb.FactionType_LIZARD, Score: 21},
+	}
+	return records
+}
+
+func TestMatchesCSVRoundTrip(t *testing.T) {
+	records := exportRecords(t)
+	for _, comma := range []rune{',', '\t'} {
+		var buf bytes.Buffer
+		require.NoError(t, writeMatchesCSV(&buf, records, comma))
+		read, err := readMatchesCSV(&buf, comma)
+		require.NoError(t, err)
+		require.Len(t, read, len(records))
+		for i := range records {
+			assert.True(t, proto.Equal(records[i], read[i]), "record %d: %v != %v", i, records[i], read[i])
+		}
+	}
+}
+
+func TestImportCSVSkipsKnownIds(t *testing.T) {
+	records := exportRecords(t)
+	var buf bytes.Buffer
+	require.NoError(t, writeMatchesCSV(&buf, records, ','))
+
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.pb"))
+	require.NoError(t, err)
+	require.NoError(t, history.Import(records[0]))
+
+	added, err := importCSV(bytes.NewReader(buf.Bytes()), ',', history)
+	require.NoError(t, err)
+	assert.Equal(t, 2, added)
+	require.Len(t, history.Records(), 3)
+	assert.Equal(t, records[2].GetId(), history.Records()[2].GetId())
+
+	added, err = importCSV(bytes.NewReader(buf.Bytes()), ',', history)
+	require.NoError(t, err)
+	assert.Zero(t, added)
+
+	// Exports from before the deck column still import.
+	old := "id,date,seats,players,bots,bot_difficulties,bot_traits,hirelings,map,landmarks,results,winner\n" +
+		"old-1,2024-03-01T20:00:00Z,1,Marquise de Cat,,,,,Lake,,,\n"
+	read, err := readMatchesCSV(strings.NewReader(old), ',')
+	require.NoError(t, err)
+	require.Len(t, read, 1)
+	assert.Equal(t, matchpb.Deck_UNKNOWN_DECK, read[0].GetMatch().GetDeck())
+
+	// So do bots named after their faction.
+	bot, err := parseBotCell(EyrieDynasties)
+	require.NoError(t, err)
+	assert.Equal(t, matchpb.BotType_ELECTRIC_EYRIE, bot.GetClockwork())
+
+	_, err = readMatchesCSV(strings.NewReader("id,date\n"), ',')
+	assert.ErrorContains(t, err, "missing the seats column")
+}
+
+func TestExportEndpoint(t *testing.T) {
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.pb"))
+	require.NoError(t, err)
+	for _, record := range exportRecords(t) {
+		require.NoError(t, history.Import(record))
+	}
+	e := newServer(history, &Campaigns{})
+
+	req := httptest.NewRequest(http.MethodGet, "/api/matches/export.tsv", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/tab-separated-values")
+	lines := strings.Split(strings.TrimSpace(rec.Body.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, strings.Join(exportColumns, "\t"), lines[0])
+
+	req = httptest.NewRequest(http.MethodGet, "/api/matches/export.csv", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/csv")
+	read, err := readMatchesCSV(rec.Body, ',')
+	require.NoError(t, err)
+	assert.Len(t, read, 3)
+}
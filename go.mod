@@ -6,6 +6,7 @@ require (
 	github.com/a-h/templ v0.2.793
 	github.com/labstack/echo v3.3.10+incompatible
 	github.com/stretchr/testify v1.10.0
+	google.golang.org/grpc v1.67.1
 	google.golang.org/protobuf v1.35.2
 )
 
@@ -21,5 +22,6 @@ require (
 	golang.org/x/net v0.33.0 // indirect
 	golang.org/x/sys v0.28.0 // indirect
 	golang.org/x/text v0.21.0 // indirect
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20240814211410-ddb44dafa142 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net"
+
+	"LegacyRoot/matchpb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// matchServer serves the MatchService from the same generator and history as
+// the web server.
+type matchServer struct {
+	matchpb.UnimplementedMatchServiceServer
+	history *History
+}
+
+func newGRPCServer(history *History) *grpc.Server {
+	s := grpc.NewServer()
+	matchpb.RegisterMatchServiceServer(s, &matchServer{history: history})
+	return s
+}
+
+func serveGRPC(addr string, history *History) error {
+	lis, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return newGRPCServer(history).Serve(lis)
+}
+
+func (s *matchServer) GenerateMatch(ctx context.Context, req *matchpb.GenerateMatchRequest) (*matchpb.MatchRecord, error) {
+	prev, err := previousMatch(s.history)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to load previous match: %v", err)
+	}
+	cfg, seed := matchCfgFromProto(req.GetConfig()), newSeed()
+	// Request configs have no defaults, any bots were asked for.
+	if err := checkAdsetBots(cfg); err != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "%v", err)
+	}
+	coverHistory(s.history, cfg)
+	match, used, err := generateMatch(prev, cfg, seed)
+	var violations ValidationError
+	if errors.As(err, &violations) {
+		return nil, status.Errorf(codes.Internal, "failed to generate match: %v", err)
+	}
+	if err != nil {
+		// Anything else is a config no match can satisfy.
+		return nil, status.Errorf(codes.InvalidArgument, "failed to generate match: %v", err)
+	}
+	record, err := s.history.AddGenerated(match, seed, used)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to store match: %v", err)
+	}
+	return record, nil
+}
+
+func (s *matchServer) GetMatch(ctx context.Context, req *matchpb.GetMatchRequest) (*matchpb.MatchRecord, error) {
+	record, ok := s.history.Get(req.GetId())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no match with id %q", req.GetId())
+	}
+	return record, nil
+}
+
+func (s *matchServer) ListMatches(ctx context.Context, req *matchpb.ListMatchesRequest) (*matchpb.ListMatchesResponse, error) {
+	records := s.history.Records()
+	resp := &matchpb.ListMatchesResponse{}
+	for i := len(records) - 1; i >= 0; i-- {
+		if req.GetLimit() > 0 && len(resp.Matches) == int(req.GetLimit()) {
+			break
+		}
+		resp.Matches = append(resp.Matches, records[i])
+	}
+	return resp, nil
+}
+
+func (s *matchServer) RecordResult(ctx context.Context, req *matchpb.RecordResultRequest) (*matchpb.MatchRecord, error) {
+	if _, ok := s.history.Get(req.GetId()); !ok {
+		return nil, status.Errorf(codes.NotFound, "no match with id %q", req.GetId())
+	}
+	record, err := s.history.Modify(req.GetId(), func(record *matchpb.MatchRecord) error {
+		record.Results = req.GetResults()
+		return nil
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to store result: %v", err)
+	}
+	return record, nil
+}
+
+func (s *matchServer) RerollComponent(ctx context.Context, req *matchpb.RerollComponentRequest) (*matchpb.MatchRecord, error) {
+	if _, ok := matchpb.Component_name[int32(req.GetComponent())]; !ok || req.GetComponent() == matchpb.Component_COMPONENT_UNSPECIFIED {
+		return nil, status.Errorf(codes.InvalidArgument, "component %v can't be rerolled", req.GetComponent())
+	}
+	env, ok := s.history.Envelope(req.GetId())
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "no match with id %q", req.GetId())
+	}
+	// Without a config the match is rerolled with the one it was generated
+	// with.
+	cfg := storedCfg(env)
+	if req.GetConfig() != nil {
+		cfg = matchCfgFromProto(req.GetConfig())
+	}
+	coverHistory(s.history, cfg)
+	var rerollErr error
+	env, err := s.history.ModifyEnvelope(req.GetId(), func(env *matchpb.Envelope) error {
+		var match *matchpb.Match
+		match, rerollErr = rerollComponent(rand.New(rand.NewSource(newSeed())), env.GetRecord().GetMatch(), req.GetComponent(), cfg)
+		if rerollErr != nil {
+			return rerollErr
+		}
+		setRerolled(env, match, cfg)
+		return nil
+	})
+	if rerollErr != nil {
+		return nil, status.Errorf(codes.InvalidArgument, "failed to reroll match: %v", rerollErr)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to store match: %v", err)
+	}
+	return env.GetRecord(), nil
+}
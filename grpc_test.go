@@ -0,0 +1,145 @@
+package main
+
+import (
+	"LegacyRoot/matchpb"
+	"context"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newTestClient(t *testing.T) matchpb.MatchServiceClient {
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.jsonl"))
+	require.NoError(t, err)
+
+	lis := bufconn.Listen(1 << 20)
+	s := newGRPCServer(history)
+	go s.Serve(lis)
+	t.Cleanup(s.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return matchpb.NewMatchServiceClient(conn)
+}
+
+var testConfig = &matchpb.MatchConfig{
+	UseHirelings:  true,
+	UseLandmarks:  true,
+	Players:       1,
+	BotEnemies:    2,
+	MaxDifficulty: matchpb.BotDifficulty_NIGHTMARE,
+	MaxBotTraits:  1,
+}
+
+func TestGRPCGenerateAndGetMatch(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	generated, err := client.GenerateMatch(ctx, &matchpb.GenerateMatchRequest{Config: testConfig})
+	require.NoError(t, err)
+	assert.NotEmpty(t, generated.GetId())
+	assert.Len(t, generated.GetMatch().GetPlayers(), 1)
+	assert.Len(t, generated.GetMatch().GetBots(), 2)
+
+	got, err := client.GetMatch(ctx, &matchpb.GetMatchRequest{Id: generated.GetId()})
+	require.NoError(t, err)
+	assert.Equal(t, generated.GetMatch().GetPlayers()[0].GetType(), got.GetMatch().GetPlayers()[0].GetType())
+
+	_, err = client.GetMatch(ctx, &matchpb.GetMatchRequest{Id: "missing"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	// Configs no match can satisfy are the caller's to fix.
+	_, err = client.GenerateMatch(ctx, &matchpb.GenerateMatchRequest{Config: &matchpb.MatchConfig{Players: 1, BotEnemies: 20}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	_, err = client.GenerateMatch(ctx, &matchpb.GenerateMatchRequest{Config: &matchpb.MatchConfig{Players: 2, BotEnemies: 1, Setup: matchpb.SetupMode_ADSET}})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGRPCListMatches(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	ids := []string{}
+	for range 3 {
+		record, err := client.GenerateMatch(ctx, &matchpb.GenerateMatchRequest{Config: testConfig})
+		require.NoError(t, err)
+		ids = append(ids, record.GetId())
+	}
+
+	all, err := client.ListMatches(ctx, &matchpb.ListMatchesRequest{})
+	require.NoError(t, err)
+	assert.Len(t, all.GetMatches(), 3)
+	assert.Equal(t, ids[2], all.GetMatches()[0].GetId())
+
+	limited, err := client.ListMatches(ctx, &matchpb.ListMatchesRequest{Limit: 2})
+	require.NoError(t, err)
+	assert.Len(t, limited.GetMatches(), 2)
+}
+
+func TestGRPCRecordResult(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	record, err := client.GenerateMatch(ctx, &matchpb.GenerateMatchRequest{Config: testConfig})
+	require.NoError(t, err)
+
+	results := []*matchpb.Result{{
+		Player:  "Ana",
+		Faction: record.GetMatch().GetPlayers()[0].GetType(),
+		Score:   30,
+		Winner:  true,
+	}}
+	_, err = client.RecordResult(ctx, &matchpb.RecordResultRequest{Id: record.GetId(), Results: results})
+	require.NoError(t, err)
+
+	got, err := client.GetMatch(ctx, &matchpb.GetMatchRequest{Id: record.GetId()})
+	require.NoError(t, err)
+	require.Len(t, got.GetResults(), 1)
+	assert.Equal(t, "Ana", got.GetResults()[0].GetPlayer())
+	assert.True(t, got.GetResults()[0].GetWinner())
+}
+
+func TestGRPCRerollComponent(t *testing.T) {
+	client := newTestClient(t)
+	ctx := context.Background()
+
+	record, err := client.GenerateMatch(ctx, &matchpb.GenerateMatchRequest{Config: testConfig})
+	require.NoError(t, err)
+
+	rerolled, err := client.RerollComponent(ctx, &matchpb.RerollComponentRequest{
+		Id:        record.GetId(),
+		Component: matchpb.Component_BOTS,
+		Config:    testConfig,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, record.GetMatch().GetPlayers()[0].GetType(), rerolled.GetMatch().GetPlayers()[0].GetType())
+	assert.Equal(t, record.GetMatch().GetMap().GetType(), rerolled.GetMatch().GetMap().GetType())
+	assert.Len(t, rerolled.GetMatch().GetBots(), 2)
+	for _, bot := range rerolled.GetMatch().GetBots() {
+		assert.NotEqual(t, rerolled.GetMatch().GetPlayers()[0].GetType(), bot.GetType())
+	}
+
+	// Without a config the match keeps the one it was generated with, two bots
+	// rather than the default one.
+	rerolled, err = client.RerollComponent(ctx, &matchpb.RerollComponentRequest{Id: record.GetId(), Component: matchpb.Component_BOTS})
+	require.NoError(t, err)
+	assert.Len(t, rerolled.GetMatch().GetBots(), 2)
+
+	// Leaving the component out doesn't reroll the players.
+	_, err = client.RerollComponent(ctx, &matchpb.RerollComponentRequest{Id: record.GetId(), Config: testConfig})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
@@ -0,0 +1,309 @@
+package main
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"runtime/debug"
+	"slices"
+	"sync"
+	"time"
+
+	"LegacyRoot/matchpb"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Version 1 histories held bare match records, one protojson record per line.
+// Version 2 wraps every record in an envelope.
+const historySchemaVersion = 2
+
+// History keeps every generated match. It is stored as length delimited
+// binary envelopes, or as one protojson envelope per line when the file has a
+// .jsonl extension so it can be edited by hand.
+type History struct {
+	mu        sync.Mutex
+	path      string
+	envelopes []*matchpb.Envelope
+}
+
+func openHistory(path string) (*History, error) {
+	envelopes, err := readStore(path, "history", func() *matchpb.Envelope { return &matchpb.Envelope{} }, unmarshalEnvelopeJSON)
+	if err != nil {
+		return nil, err
+	}
+	for _, env := range envelopes {
+		migrateEnvelope(env)
+	}
+	return &History{path: path, envelopes: envelopes}, nil
+}
+
+// unmarshalEnvelopeJSON reads a line of a .jsonl history. Version 1 lines
+// are bare records.
+func unmarshalEnvelopeJSON(line []byte, env *matchpb.Envelope) error {
+	err := protojson.Unmarshal(line, env)
+	if err == nil {
+		return nil
+	}
+	record := &matchpb.MatchRecord{}
+	if protojson.Unmarshal(line, record) != nil {
+		return err
+	}
+	proto.Reset(env)
+	env.SchemaVersion, env.Record = 1, record
+	return nil
+}
+
+// migrateEnvelope upgrades an envelope written by an older schema version.
+func migrateEnvelope(env *matchpb.Envelope) {
+	if env.GetSchemaVersion() < 2 && env.GetRecord().GetMatch() != nil {
+		// Version 1 records may predate bot and hireling setup details.
+		fillMatch(env.GetRecord().GetMatch(), &[]string{})
+	}
+	// Bots stored before they recorded their Clockwork bot play the one
+	// mirroring their faction.
+	for _, bot := range env.GetRecord().GetMatch().GetBots() {
+		if bot.GetClockwork() == matchpb.BotType_BOT_TYPE_UNSPECIFIED {
+			bot.Clockwork = mirroringBot(bot.GetType())
+		}
+	}
+	env.SchemaVersion = historySchemaVersion
+}
+
+func generatorVersion() string {
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return "unknown"
+	}
+	for _, setting := range info.Settings {
+		if setting.Key == "vcs.revision" {
+			return setting.Value
+		}
+	}
+	return info.Main.Version
+}
+
+// Words match ids are made of, so they can be read out across the table.
+var (
+	idPlaces = []string{
+		"autumn", "winter", "lake", "mountain", "ferry", "tower", "forge", "market",
+		"treetop", "city", "roost", "burrow", "garden", "keep", "clearing", "river",
+	}
+	idAnimals = []string{
+		"cat", "eagle", "mouse", "rabbit", "fox", "otter", "lizard", "mole",
+		"crow", "rat", "badger", "raccoon", "owl", "hedgehog", "squirrel", "frog",
+	}
+)
+
+// idAttempts is how many ids unusedMatchId tries before making the number at
+// the end of them longer.
+const idAttempts = 100
+
+// newMatchId returns an id like ferry-otter-42.
+func newMatchId() string {
+	return matchIdWithDigits(2)
+}
+
+func matchIdWithDigits(digits int) string {
+	lowest := 1
+	for range digits - 1 {
+		lowest *= 10
+	}
+	return fmt.Sprintf("%s-%s-%d", idPlaces[rand.IntN(len(idPlaces))], idAnimals[rand.IntN(len(idAnimals))], lowest+rand.IntN(9*lowest))
+}
+
+// unusedMatchId returns a new id that isn't taken, going on to longer numbers
+// once short ids keep colliding.
+func unusedMatchId(taken func(string) bool) string {
+	for digits := 2; ; digits++ {
+		for range idAttempts {
+			if id := matchIdWithDigits(digits); !taken(id) {
+				return id
+			}
+		}
+	}
+}
+
+// Add stores a match under a new id. Ids are never reused, so links to a
+// match keep working.
+func (h *History) Add(match *matchpb.Match) (*matchpb.MatchRecord, error) {
+	return h.AddAt(match, time.Now())
+}
+
+// AddAt stores a match created at the given time under a new id.
+func (h *History) AddAt(match *matchpb.Match, createdAt time.Time) (*matchpb.MatchRecord, error) {
+	return h.add(&matchpb.Envelope{
+		Record: &matchpb.MatchRecord{
+			CreatedAt: timestamppb.New(createdAt),
+			Match:     match,
+		},
+	})
+}
+
+// AddGenerated stores a generated match along with the seed and config it
+// was generated from.
+func (h *History) AddGenerated(match *matchpb.Match, seed int64, cfg *MatchCfg) (*matchpb.MatchRecord, error) {
+	return h.add(&matchpb.Envelope{
+		Seed:   seed,
+		Config: matchCfgToProto(cfg),
+		Record: &matchpb.MatchRecord{
+			CreatedAt: timestamppb.Now(),
+			Match:     match,
+		},
+	})
+}
+
+// Import stores a record as it is, keeping its id and creation time. Records
+// without an id get a new one.
+func (h *History) Import(record *matchpb.MatchRecord) error {
+	_, err := h.add(&matchpb.Envelope{Record: record})
+	return err
+}
+
+func (h *History) add(env *matchpb.Envelope) (*matchpb.MatchRecord, error) {
+	env.SchemaVersion = historySchemaVersion
+	env.GeneratorVersion = generatorVersion()
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if env.GetRecord().GetId() == "" {
+		env.Record.Id = h.unusedId()
+	}
+	if err := appendStore(h.path, "history", env); err != nil {
+		return nil, err
+	}
+	h.envelopes = append(h.envelopes, env)
+	return env.GetRecord(), nil
+}
+
+func (h *History) unusedId() string {
+	return unusedMatchId(func(id string) bool {
+		return slices.ContainsFunc(h.envelopes, func(env *matchpb.Envelope) bool { return env.GetRecord().GetId() == id })
+	})
+}
+
+// Modify applies modify to a copy of the record with the given id and
+// rewrites the history with it, all under the lock so concurrent changes to
+// the record aren't lost. Nothing is stored when modify fails.
+func (h *History) Modify(id string, modify func(*matchpb.MatchRecord) error) (*matchpb.MatchRecord, error) {
+	env, err := h.ModifyEnvelope(id, func(env *matchpb.Envelope) error { return modify(env.GetRecord()) })
+	if err != nil {
+		return nil, err
+	}
+	return env.GetRecord(), nil
+}
+
+// ModifyEnvelope is Modify for the whole envelope, for changes to the seed
+// or config a match was generated with.
+func (h *History) ModifyEnvelope(id string, modify func(*matchpb.Envelope) error) (*matchpb.Envelope, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	i := slices.IndexFunc(h.envelopes, func(env *matchpb.Envelope) bool {
+		return env.GetRecord().GetId() == id
+	})
+	if i < 0 {
+		return nil, fmt.Errorf("no match with id %q", id)
+	}
+
+	env := proto.Clone(h.envelopes[i]).(*matchpb.Envelope)
+	if err := modify(env); err != nil {
+		return nil, err
+	}
+	envelopes := slices.Clone(h.envelopes)
+	envelopes[i] = env
+	if err := writeStore(h.path, "history", envelopes); err != nil {
+		return nil, err
+	}
+	h.envelopes = envelopes
+	return env, nil
+}
+
+// Remove drops the record with the given id and rewrites the history, for
+// matches whose generation is taken back before anyone saw them.
+func (h *History) Remove(id string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	envelopes := slices.DeleteFunc(slices.Clone(h.envelopes), func(env *matchpb.Envelope) bool {
+		return env.GetRecord().GetId() == id
+	})
+	if len(envelopes) == len(h.envelopes) {
+		return fmt.Errorf("no match with id %q", id)
+	}
+	if err := writeStore(h.path, "history", envelopes); err != nil {
+		return err
+	}
+	h.envelopes = envelopes
+	return nil
+}
+
+// SaveAs writes the whole history to path, in the format its extension asks
+// for.
+func (h *History) SaveAs(path string) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return writeStore(path, "history", h.envelopes)
+}
+
+// Envelopes returns the stored envelopes, oldest first.
+func (h *History) Envelopes() []*matchpb.Envelope {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return slices.Clone(h.envelopes)
+}
+
+// Records returns the stored records, oldest first.
+func (h *History) Records() []*matchpb.MatchRecord {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	records := []*matchpb.MatchRecord{}
+	for _, env := range h.envelopes {
+		records = append(records, env.GetRecord())
+	}
+	return records
+}
+
+func (h *History) Get(id string) (*matchpb.MatchRecord, bool) {
+	env, ok := h.Envelope(id)
+	return env.GetRecord(), ok
+}
+
+// setRerolled stores a rerolled match in env along with the config it was
+// rerolled with. The seed no longer generates the match, so it is cleared.
+func setRerolled(env *matchpb.Envelope, match *matchpb.Match, cfg *MatchCfg) {
+	env.Record.Match = match
+	env.Seed = 0
+	env.Config = matchCfgToProto(cfg)
+}
+
+// Envelope returns the envelope of the match with the given id.
+func (h *History) Envelope(id string) (*matchpb.Envelope, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, env := range h.envelopes {
+		if env.GetRecord().GetId() == id {
+			return env, true
+		}
+	}
+	return nil, false
+}
+
+// storedCfg is the config a stored match was generated with, the default one
+// for matches that weren't generated.
+func storedCfg(env *matchpb.Envelope) *MatchCfg {
+	if env.GetConfig() == nil {
+		cfg := defaultMatchCfg()
+		return &cfg
+	}
+	return matchCfgFromProto(env.GetConfig())
+}
+
+// Last returns the most recent match, nil when the history is empty.
+func (h *History) Last() *matchpb.Match {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.envelopes) == 0 {
+		return nil
+	}
+	return h.envelopes[len(h.envelopes)-1].GetRecord().GetMatch()
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"LegacyRoot/matchpb"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGenerateMatchSeed(t *testing.T) {
+	prev, err := parseMatch("match.json")
+	require.NoError(t, err)
+	cfg := defaultMatchCfg()
+	cfg.BotEnemies = 2
+
+	first, _, err := generateMatch(prev, &cfg, 42)
+	require.NoError(t, err)
+	for range 5 {
+		again, _, err := generateMatch(prev, &cfg, 42)
+		require.NoError(t, err)
+		assert.True(t, proto.Equal(first, again))
+	}
+}
+
+func TestHistoryBinaryEnvelopes(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.pb")
+	history, err := openHistory(path)
+	require.NoError(t, err)
+
+	cfg := defaultMatchCfg()
+	match, _, err := generateMatch(&matchpb.Match{}, &cfg, 7)
+	require.NoError(t, err)
+	record, err := history.AddGenerated(match, 7, &cfg)
+	require.NoError(t, err)
+	_, err = history.Add(&matchpb.Match{Players: []*matchpb.Faction{NewFaction(Lizard)}})
+	require.NoError(t, err)
+
+	history, err = openHistory(path)
+	require.NoError(t, err)
+	envelopes := history.Envelopes()
+	require.Len(t, envelopes, 2)
+	assert.Equal(t, int32(historySchemaVersion), envelopes[0].GetSchemaVersion())
+	assert.Equal(t, int64(7), envelopes[0].GetSeed())
+	assert.True(t, proto.Equal(matchCfgToProto(&cfg), envelopes[0].GetConfig()))
+	assert.True(t, proto.Equal(record, envelopes[0].GetRecord()))
+	assert.Equal(t, *matchCfgFromProto(envelopes[0].GetConfig()), cfg)
+
+	// Modifying a record keeps what it was generated from.
+	_, err = history.Modify(record.GetId(), func(record *matchpb.MatchRecord) error {
+		record.Results = []*matchpb.Result{{Player: "Ana", Score: 30, Winner: true}}
+		return nil
+	})
+	require.NoError(t, err)
+	_, err = history.Modify(record.GetId(), func(record *matchpb.MatchRecord) error {
+		return errors.New("not stored")
+	})
+	assert.Error(t, err)
+	history, err = openHistory(path)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), history.Envelopes()[0].GetSeed())
+	assert.Len(t, history.Records()[0].GetResults(), 1)
+
+	// A rerolled match no longer comes from the seed, and keeps the config it
+	// was rerolled with.
+	rerollCfg := cfg
+	rerollCfg.BotEnemies = 3
+	_, _, err = rerollStored(history, record.GetId(), matchpb.Component_BOTS, &rerollCfg)
+	require.NoError(t, err)
+	env, _ := history.Envelope(record.GetId())
+	assert.Zero(t, env.GetSeed())
+	assert.Equal(t, int32(3), env.GetConfig().GetBotEnemies())
+	assert.Len(t, env.GetRecord().GetResults(), 1)
+}
+
+func TestHistoryMigratesVersion1(t *testing.T) {
+	dir := t.TempDir()
+	old := filepath.Join(dir, "history.jsonl")
+	line := `{"Id": "abc", "Match": {"Players": [{"Type": "EYRIE", "Name": "Eyrie Dynasties"}],` +
+		` "Hirelings": [{"Type": "ALLIANCE", "Name": "Rabbit Scouts"}]}}` + "\n"
+	require.NoError(t, os.WriteFile(old, []byte(line), 0o644))
+
+	history, err := openHistory(old)
+	require.NoError(t, err)
+	env := history.Envelopes()[0]
+	assert.Equal(t, int32(historySchemaVersion), env.GetSchemaVersion())
+	assert.Equal(t, "abc", env.GetRecord().GetId())
+	assert.Equal(t, matchpb.HirelingStatus_DEMOTED, env.GetRecord().GetMatch().GetHirelings()[0].GetStatus())
+	assert.Empty(t, Validate(env.GetRecord().GetMatch()))
+
+	migrated := filepath.Join(dir, "history.pb")
+	require.NoError(t, history.SaveAs(migrated))
+	history, err = openHistory(migrated)
+	require.NoError(t, err)
+	require.Len(t, history.Envelopes(), 1)
+	assert.True(t, proto.Equal(env, history.Envelopes()[0]))
+}
+
+func TestHistoryFillsClockwork(t *testing.T) {
+	// Bots stored before Clockwork was recorded, or stored as a Mechanical
+	// Marquise back when that was the zero value, decode unspecified.
+	path := filepath.Join(t.TempDir(), "history.pb")
+	require.NoError(t, writeStore(path, "history", []*matchpb.Envelope{{
+		SchemaVersion: historySchemaVersion,
+		Record: &matchpb.MatchRecord{Id: "abc", Match: &matchpb.Match{Bots: []*matchpb.Bot{
+			{Type: matchpb.FactionType_MARQUISE, Name: "Mechanical Marquise 2.0"},
+			{Type: matchpb.FactionType_EYRIE, Name: "Eyrie Dynasties"},
+		}}},
+	}}))
+
+	history, err := openHistory(path)
+	require.NoError(t, err)
+	bots := history.Records()[0].GetMatch().GetBots()
+	assert.Equal(t, matchpb.BotType_MECHANICAL_MARQUISE, bots[0].GetClockwork())
+	assert.Equal(t, matchpb.BotType_ELECTRIC_EYRIE, bots[1].GetClockwork())
+}
+
+func TestUnusedMatchIdGrowsLonger(t *testing.T) {
+	// With every short id taken the number at the end gets longer.
+	id := unusedMatchId(func(id string) bool { return regexp.MustCompile(`-\d{2}$`).MatchString(id) })
+	assert.Regexp(t, `^[a-z]+-[a-z]+-\d{3}$`, id)
+	assert.Regexp(t, `^[a-z]+-[a-z]+-[1-9]\d$`, newMatchId())
+}
@@ -0,0 +1,231 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"hash/crc32"
+	"math/rand"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	"LegacyRoot/matchpb"
+
+	"github.com/a-h/templ"
+	"github.com/labstack/echo"
+	"google.golang.org/protobuf/proto"
+)
+
+const historyPageSize = 20
+
+// isHTMX reports whether the request asks for a fragment to swap in.
+func isHTMX(c echo.Context) bool {
+	return c.Request().Header.Get("HX-Request") == "true"
+}
+
+// matchCfgFromForm reads MatchCfg options named like the CLI flags, keeping
+// the defaults for options the form leaves out. The last value of a repeated
+// option wins.
+func matchCfgFromForm(values url.Values) (*MatchCfg, error) {
+	fs := flag.NewFlagSet("cfg", flag.ContinueOnError)
+	cfg := matchCfgFlags(fs)
+	for _, name := range sortedKeys(values) {
+		if fs.Lookup(name) == nil || len(values[name]) == 0 {
+			continue
+		}
+		if err := fs.Set(name, values[name][len(values[name])-1]); err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+	}
+	return cfg, nil
+}
+
+func formCfg(c echo.Context) (*MatchCfg, error) {
+	if err := c.Request().ParseForm(); err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	cfg, err := matchCfgFromForm(c.Request().Form)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, err.Error())
+	}
+	return cfg, nil
+}
+
+// matchVersion changes whenever the match does, so rerolled board images
+// aren't served from the browser cache.
+func matchVersion(match *matchpb.Match) string {
+	data, _ := proto.MarshalOptions{Deterministic: true}.Marshal(match)
+	return strconv.FormatUint(uint64(crc32.ChecksumIEEE(data)), 16)
+}
+
+// rerollURL is where the components of a stored match are rerolled.
+func rerollURL(record *matchpb.MatchRecord) string {
+	return "/matches/" + record.GetId() + "/reroll/"
+}
+
+func generatorHandler(history *History) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		cfg, err := formCfg(c)
+		if err != nil {
+			return err
+		}
+		var last *matchpb.MatchRecord
+		if records := history.Records(); len(records) > 0 {
+			last = records[len(records)-1]
+		}
+		return renderComponent(c, generatorPage(cfg, last))
+	}
+}
+
+// generateStored generates a match following on from the last one and stores
+// it.
+func generateStored(history *History, cfg *MatchCfg) (*matchpb.MatchRecord, error) {
+	prev, err := previousMatch(history)
+	if err != nil {
+		return nil, err
+	}
+	coverHistory(history, cfg)
+	seed := newSeed()
+	match, used, err := generateMatch(prev, cfg, seed)
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+	}
+	return history.AddGenerated(match, seed, used)
+}
+
+// rerollStored rerolls one component of a stored match and stores the result.
+// A nil cfg rerolls with the config the match was generated with. The config
+// used is returned along with the record.
+func rerollStored(history *History, id string, component matchpb.Component, cfg *MatchCfg) (*matchpb.MatchRecord, *MatchCfg, error) {
+	env, ok := history.Envelope(id)
+	if !ok {
+		return nil, nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("no match with id %q", id))
+	}
+	if cfg == nil {
+		cfg = storedCfg(env)
+	}
+	coverHistory(history, cfg)
+	rng := rand.New(rand.NewSource(newSeed()))
+	env, err := history.ModifyEnvelope(id, func(env *matchpb.Envelope) error {
+		match, err := rerollComponent(rng, env.GetRecord().GetMatch(), component, cfg)
+		if err != nil {
+			return echo.NewHTTPError(http.StatusUnprocessableEntity, err.Error())
+		}
+		setRerolled(env, match, cfg)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return env.GetRecord(), cfg, nil
+}
+
+// rerollFormCfg reads the config a reroll asks for, nil when the form sets
+// none of its options so the match keeps the config it was generated with.
+func rerollFormCfg(c echo.Context) (*MatchCfg, error) {
+	cfg, err := formCfg(c)
+	if err != nil {
+		return nil, err
+	}
+	fs := flag.NewFlagSet("cfg", flag.ContinueOnError)
+	matchCfgFlags(fs)
+	for name := range c.Request().Form {
+		if fs.Lookup(name) != nil {
+			return cfg, nil
+		}
+	}
+	return nil, nil
+}
+
+// componentParam reads the component named by the component path parameter.
+func componentParam(c echo.Context) (matchpb.Component, error) {
+	value, ok := matchpb.Component_value[strings.ToUpper(c.Param("component"))]
+	if !ok || value == int32(matchpb.Component_COMPONENT_UNSPECIFIED) {
+		return 0, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("unknown component %q", c.Param("component")))
+	}
+	return matchpb.Component(value), nil
+}
+
+// generateHandler generates and stores a match, answering HTMX requests with
+// the match fragment and others with a redirect to its permalink.
+func generateHandler(history *History) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		cfg, err := formCfg(c)
+		if err != nil {
+			return err
+		}
+		record, err := generateStored(history, cfg)
+		if err != nil {
+			return err
+		}
+		if isHTMX(c) {
+			return renderComponent(c, matchView(record, rerollURL(record)))
+		}
+		return c.Redirect(http.StatusSeeOther, "/m/"+record.GetId())
+	}
+}
+
+var componentSections = map[matchpb.Component]func(*matchpb.MatchRecord, string) templ.Component{
+	matchpb.Component_PLAYERS:   playersSection,
+	matchpb.Component_BOTS:      botsSection,
+	matchpb.Component_HIRELINGS: hirelingsSection,
+	matchpb.Component_MAP:       mapSection,
+	matchpb.Component_LANDMARKS: landmarksSection,
+	matchpb.Component_DECK:      deckSection,
+	matchpb.Component_SEATS:     seatsSection,
+}
+
+// rerollHandler rerolls one component of a stored match, answering HTMX
+// requests with just that component's fragment.
+func rerollHandler(history *History) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		record, err := recordParam(c, history)
+		if err != nil {
+			return err
+		}
+		component, err := componentParam(c)
+		if err != nil {
+			return err
+		}
+		cfg, err := rerollFormCfg(c)
+		if err != nil {
+			return err
+		}
+
+		record, _, err = rerollStored(history, record.GetId(), component, cfg)
+		if err != nil {
+			return err
+		}
+		if isHTMX(c) {
+			return renderComponent(c, rerollFragment(component, record))
+		}
+		return c.Redirect(http.StatusSeeOther, "/m/"+record.GetId())
+	}
+}
+
+// historyHandler lists stored matches newest first, a page at a time.
+func historyHandler(history *History) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		offset := 0
+		if s := c.QueryParam("offset"); s != "" {
+			var err error
+			if offset, err = strconv.Atoi(s); err != nil || offset < 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid offset %q", s))
+			}
+		}
+		records := history.Records()
+		slices.Reverse(records)
+		records = records[min(offset, len(records)):]
+		next := ""
+		if len(records) > historyPageSize {
+			records = records[:historyPageSize]
+			next = fmt.Sprintf("/history?offset=%d", offset+historyPageSize)
+		}
+		if isHTMX(c) {
+			return renderComponent(c, historyRows(records, next))
+		}
+		return renderComponent(c, historyPage(records, next))
+	}
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"LegacyRoot/matchpb"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchCfgFromForm(t *testing.T) {
+	cfg, err := matchCfgFromForm(url.Values{
+		"bots":           {"3"},
+		"min-difficulty": {"challenging"},
+		"hirelings":      {"false", "true"},
+		"landmarks":      {"false"},
+		"unknown":        {"1"},
+	})
+	require.NoError(t, err)
+	want := defaultMatchCfg()
+	want.BotEnemies = 3
+	want.MinDifficulty = matchpb.BotDifficulty_CHALLENGING
+	want.UseLandmarks = false
+	assert.Equal(t, want, *cfg)
+
+	_, err = matchCfgFromForm(url.Values{"bots": {"many"}})
+	assert.ErrorContains(t, err, "invalid bots")
+}
+
+func htmxServer(t *testing.T) (*History, func(method, target string, body url.Values, htmx bool) *httptest.ResponseRecorder) {
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.pb"))
+	require.NoError(t, err)
+	e := newServer(history, &Campaigns{})
+	return history, func(method, target string, body url.Values, htmx bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, target, strings.NewReader(body.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		if htmx {
+			req.Header.Set("HX-Request", "true")
+		}
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+}
+
+func TestGenerateFragment(t *testing.T) {
+	history, do := htmxServer(t)
+
+	rec := do(http.MethodPost, "/match", url.Values{"bots": {"2"}, "landmarks": {"false"}}, true)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Body.String(), `<div id="match">`))
+	record := history.Records()[0]
+	assert.Len(t, record.GetMatch().GetBots(), 2)
+	assert.Empty(t, record.GetMatch().GetLandmarks())
+
+	rec = do(http.MethodGet, "/", nil, false)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `<form id="cfg"`)
+	assert.Contains(t, rec.Body.String(), record.GetId())
+
+	// ADSET with the form's default single player seats two.
+	rec = do(http.MethodPost, "/match", url.Values{"setup": {"adset"}, "players": {"1"}}, true)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Len(t, history.Records()[1].GetMatch().GetPlayers(), 2)
+
+	// Looking at the URL doesn't store a match.
+	rec = do(http.MethodGet, "/match", nil, false)
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	assert.Len(t, history.Records(), 2)
+}
+
+func TestRerollFragment(t *testing.T) {
+	history, do := htmxServer(t)
+	record, err := history.Add(&matchpb.Match{
+		Players: []*matchpb.Faction{NewFaction(Marquise)},
+		Map:     &matchpb.MapVal{Type: matchpb.MapType_AUTUMN, Name: "Autumn"},
+	})
+	require.NoError(t, err)
+
+	rec := do(http.MethodPost, "/matches/"+record.GetId()+"/reroll/landmarks", url.Values{"landmarks": {"true"}}, true)
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.True(t, strings.HasPrefix(body, `<section id="landmarks">`))
+	assert.NotContains(t, body, `id="players"`)
+	assert.Contains(t, body, `<div id="difficulty" hx-swap-oob`)
+	assert.Contains(t, body, `<img id="board" hx-swap-oob`)
+
+	rerolled, _ := history.Get(record.GetId())
+	assert.Equal(t, "Marquise de Cat", rerolled.GetMatch().GetPlayers()[0].GetName())
+
+	rec = do(http.MethodPost, "/matches/"+record.GetId()+"/reroll/map", nil, false)
+	assert.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Equal(t, "/m/"+record.GetId(), rec.Header().Get("Location"))
+
+	rec = do(http.MethodPost, "/matches/"+record.GetId()+"/reroll/deck", nil, true)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.True(t, strings.HasPrefix(rec.Body.String(), `<section id="deck">`))
+
+	// Without config options in the form a generated match is rerolled with
+	// the config it was generated with.
+	cfg := defaultMatchCfg()
+	cfg.BotEnemies = 3
+	generated, err := generateStored(history, &cfg)
+	require.NoError(t, err)
+	rec = do(http.MethodPost, "/matches/"+generated.GetId()+"/reroll/bots", nil, true)
+	require.Equal(t, http.StatusOK, rec.Code)
+	rerolled, _ = history.Get(generated.GetId())
+	assert.Len(t, rerolled.GetMatch().GetBots(), 3)
+
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPost, "/matches/"+record.GetId()+"/reroll/tokens", nil, true).Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPost, "/matches/"+record.GetId()+"/reroll/component_unspecified", nil, true).Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPost, "/matches/missing/reroll/map", nil, true).Code)
+}
+
+func TestHistoryInfiniteScroll(t *testing.T) {
+	history, do := htmxServer(t)
+	for range historyPageSize + 5 {
+		_, err := history.Add(&matchpb.Match{Players: []*matchpb.Faction{NewFaction(Eyrie)}})
+		require.NoError(t, err)
+	}
+
+	rec := do(http.MethodGet, "/history", nil, false)
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Equal(t, historyPageSize, strings.Count(body, "<td><a href="))
+	assert.Contains(t, body, `hx-get="/history?offset=20" hx-trigger="revealed"`)
+	// Newest first.
+	records := history.Records()
+	assert.Less(t, strings.Index(body, records[len(records)-1].GetId()), strings.Index(body, records[len(records)-2].GetId()))
+
+	rec = do(http.MethodGet, "/history?offset=20", nil, true)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, 5, strings.Count(rec.Body.String(), "<td><a href="))
+	assert.NotContains(t, rec.Body.String(), "hx-trigger")
+	assert.NotContains(t, rec.Body.String(), "<html>")
+
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/history?offset=-1", nil, false).Code)
+}
@@ -0,0 +1,254 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"LegacyRoot/matchpb"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// importMatch reads a match file written by older versions, which may use
+// different key casing, lowercase enum values or leave names and the map out.
+// It returns the match along with a description of every fix it made.
+func importMatch(data []byte) (*matchpb.Match, []string, error) {
+	raw := map[string]any{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, nil, fmt.Errorf("failed to deserialize match: %w", err)
+	}
+
+	fixes := []string{}
+	md := (&matchpb.Match{}).ProtoReflect().Descriptor()
+	normalized, err := json.Marshal(normalizeFields(md, raw, "", &fixes))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to normalize match: %w", err)
+	}
+	match := &matchpb.Match{}
+	if err := protojson.Unmarshal(normalized, match); err != nil {
+		return nil, nil, fmt.Errorf("failed to deserialize match: %w", err)
+	}
+
+	fillMatch(match, &fixes)
+	return match, fixes, nil
+}
+
+// normalizeFields renames keys to the JSON names of the message fields,
+// matching them regardless of case, and drops keys without a field.
+func normalizeFields(md protoreflect.MessageDescriptor, raw map[string]any, path string, fixes *[]string) map[string]any {
+	keys := []string{}
+	for key := range raw {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	normalized := map[string]any{}
+	for _, key := range keys {
+		value := raw[key]
+		fd := fieldByName(md, key)
+		if fd == nil {
+			*fixes = append(*fixes, fmt.Sprintf("dropped unknown field %s%s", path, key))
+			continue
+		}
+		name := fd.JSONName()
+		if key != name {
+			*fixes = append(*fixes, fmt.Sprintf("renamed %s%s to %s%s", path, key, path, name))
+		}
+		normalized[name] = normalizeValue(fd, value, path+name, fixes)
+	}
+	return normalized
+}
+
+func normalizeValue(fd protoreflect.FieldDescriptor, value any, path string, fixes *[]string) any {
+	if list, ok := value.([]any); ok && fd.IsList() {
+		for i, elem := range list {
+			list[i] = normalizeValue(fd, elem, fmt.Sprintf("%s[%d]", path, i), fixes)
+		}
+		return list
+	}
+	switch v := value.(type) {
+	case map[string]any:
+		if fd.Message() != nil {
+			return normalizeFields(fd.Message(), v, path+".", fixes)
+		}
+	case string:
+		if fd.Enum() != nil && fd.Enum().Values().ByName(protoreflect.Name(v)) == nil {
+			upper := strings.ToUpper(v)
+			if fd.Enum().Values().ByName(protoreflect.Name(upper)) != nil {
+				*fixes = append(*fixes, fmt.Sprintf("changed %s from %s to %s", path, v, upper))
+				return upper
+			}
+		}
+	}
+	return value
+}
+
+func fieldByName(md protoreflect.MessageDescriptor, key string) protoreflect.FieldDescriptor {
+	if fd := md.Fields().ByJSONName(key); fd != nil {
+		return fd
+	}
+	for i := range md.Fields().Len() {
+		fd := md.Fields().Get(i)
+		if strings.EqualFold(fd.JSONName(), key) || strings.EqualFold(string(fd.Name()), key) {
+			return fd
+		}
+	}
+	return nil
+}
+
+// fillMatch fills in whatever older matches didn't record.
+func fillMatch(match *matchpb.Match, fixes *[]string) {
+	fix := func(format string, args ...any) {
+		*fixes = append(*fixes, fmt.Sprintf(format, args...))
+	}
+
+	for i, player := range match.GetPlayers() {
+		if player.GetName() == "" {
+			player.Name = getFactionName(int32(player.GetType()))
+			fix("named Players[%d] %q", i, player.GetName())
+		}
+	}
+	for i, bot := range match.GetBots() {
+		filled := false
+		if bot.GetClockwork() == matchpb.BotType_BOT_TYPE_UNSPECIFIED {
+			bot.Clockwork = mirroringBot(bot.GetType())
+			filled = true
+		}
+		entry, ok := BotCatalog[bot.GetClockwork()]
+		if bot.GetName() == "" && ok && entry.Mirrors == int32(bot.GetType()) {
+			bot.Name = entry.Name
+			fix("named Bots[%d] %q", i, bot.GetName())
+		} else if filled {
+			fix("set Bots[%d] to %v", i, bot.GetClockwork())
+		}
+	}
+	for i, hireling := range match.GetHirelings() {
+		names := Hirelings[int32(hireling.GetType())]
+		// Hirelings used to be picked on a random side without a status.
+		if len(names) > 1 && hireling.GetName() == names[1] && hireling.GetStatus() != matchpb.HirelingStatus_DEMOTED {
+			hireling.Status = matchpb.HirelingStatus_DEMOTED
+			fix("demoted Hirelings[%d] %q", i, hireling.GetName())
+		}
+		if hireling.GetName() == "" && int(hireling.GetStatus()) < len(names) {
+			hireling.Name = names[hireling.GetStatus()]
+			fix("named Hirelings[%d] %q", i, hireling.GetName())
+		}
+		if hireling.GetThreshold() == 0 && i < len(HirelingThresholds) {
+			hireling.Threshold = HirelingThresholds[i]
+			fix("set up Hirelings[%d] at %d VP", i, hireling.GetThreshold())
+		}
+	}
+	if match.GetMap() == nil {
+		if m, ok := inferMap(match); ok {
+			match.Map = &matchpb.MapVal{Type: m, Name: MapNames[int32(m)]}
+			fix("no map, inferred %s from its landmarks", match.GetMap().GetName())
+		} else {
+			fix("no map, left unset")
+		}
+	} else if match.GetMap().GetName() == "" {
+		match.Map.Name = MapNames[int32(match.GetMap().GetType())]
+		fix("named Map %q", match.GetMap().GetName())
+	}
+	for i, landmark := range match.GetLandmarks() {
+		if landmark.GetName() == "" {
+			landmark.Name = getLandmarkName(int32(landmark.GetType()))
+			fix("named Landmarks[%d] %q", i, landmark.GetName())
+		}
+	}
+}
+
+// inferMap works out the map of an ADSET match, set up with clearings, from
+// its landmarks: ADSET only puts down the ones its map's rules ask for. Other
+// matches may have any landmark on any map.
+func inferMap(match *matchpb.Match) (matchpb.MapType, bool) {
+	if len(match.GetClearings()) == 0 || len(match.GetLandmarks()) != 1 {
+		return 0, false
+	}
+	for m, landmarks := range MapLandmarks {
+		if slices.Equal(landmarks, []int32{int32(match.GetLandmarks()[0].GetType())}) {
+			return m, true
+		}
+	}
+	return 0, false
+}
+
+type ImportResult struct {
+	File  string
+	Id    string
+	Fixes []string
+	Err   error
+	// Skipped is set for files imported before, Id being their record's.
+	Skipped bool
+}
+
+// importDir imports every .json match file in dir into the history, dated by
+// the file's modification time. Files that fail to import are reported and
+// skipped, as are files the history already holds the same match for from
+// the same time, so a directory can be imported again.
+func importDir(dir string, history *History) ([]ImportResult, error) {
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	results := []ImportResult{}
+	for _, file := range files {
+		result := ImportResult{File: file}
+		match, info, err := importFile(file, &result.Fixes)
+		if err != nil {
+			result.Err = err
+			results = append(results, result)
+			continue
+		}
+		if id, ok := importedAs(history, match, info.ModTime()); ok {
+			result.Id, result.Skipped = id, true
+			results = append(results, result)
+			continue
+		}
+		record, err := history.AddAt(match, info.ModTime())
+		if err != nil {
+			return results, err
+		}
+		result.Id = record.GetId()
+		results = append(results, result)
+	}
+	return results, nil
+}
+
+// importedAs returns the id of the record holding match created at
+// createdAt, if any.
+func importedAs(history *History, match *matchpb.Match, createdAt time.Time) (string, bool) {
+	for _, record := range history.Records() {
+		if record.GetCreatedAt().AsTime().Equal(createdAt) && proto.Equal(record.GetMatch(), match) {
+			return record.GetId(), true
+		}
+	}
+	return "", false
+}
+
+func importFile(file string, fixes *[]string) (*matchpb.Match, os.FileInfo, error) {
+	info, err := os.Stat(file)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read match file: %w", err)
+	}
+	match, fixed, err := importMatch(data)
+	*fixes = fixed
+	if err != nil {
+		return nil, nil, err
+	}
+	if violations := Validate(match); len(violations) > 0 {
+		return nil, nil, ValidationError(violations)
+	}
+	return match, info, nil
+}
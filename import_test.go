@@ -0,0 +1,110 @@
+package main
+
+import (
+	"LegacyRoot/matchpb"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportMatchNormalizes(t *testing.T) {
+	data := `{
+		"players": [{"type": "marquise"}],
+		"BOTS": [{"Type": "EYRIE"}],
+		"hirelings": [{"Type": "ALLIANCE", "Name": "Rabbit Scouts"}],
+		"landmarks": [{"Type": "FERRY"}],
+		"Notes": "played at Ana's"
+	}`
+	match, fixes, err := importMatch([]byte(data))
+	require.NoError(t, err)
+	assert.Empty(t, Validate(match))
+
+	assert.Equal(t, MarquiseDeCat, match.GetPlayers()[0].GetName())
+	assert.Equal(t, "Electric Eyrie", match.GetBots()[0].GetName())
+	assert.Equal(t, matchpb.BotType_ELECTRIC_EYRIE, match.GetBots()[0].GetClockwork())
+	assert.Equal(t, matchpb.HirelingStatus_DEMOTED, match.GetHirelings()[0].GetStatus())
+	assert.Equal(t, int32(4), match.GetHirelings()[0].GetThreshold())
+	// The Ferry can be on any map outside ADSET, so the map isn't guessed.
+	assert.Nil(t, match.GetMap())
+	assert.Equal(t, "The Ferry", match.GetLandmarks()[0].GetName())
+
+	assert.Equal(t, []string{
+		"renamed BOTS to Bots",
+		"dropped unknown field Notes",
+		"renamed hirelings to Hirelings",
+		"renamed landmarks to Landmarks",
+		"renamed players to Players",
+		"renamed Players[0].type to Players[0].Type",
+		"changed Players[0].Type from marquise to MARQUISE",
+		"named Players[0] \"Marquise de Cat\"",
+		"named Bots[0] \"Electric Eyrie\"",
+		"demoted Hirelings[0] \"Rabbit Scouts\"",
+		"set up Hirelings[0] at 4 VP",
+		"no map, left unset",
+		"named Landmarks[0] \"The Ferry\"",
+	}, fixes)
+}
+
+func TestImportMatchInfersAdsetMap(t *testing.T) {
+	data := `{
+		"Players": [{"Type": "MARQUISE"}, {"Type": "EYRIE"}],
+		"Clearings": [{"Number": 1, "Suit": "FOX"}],
+		"Landmarks": [{"Type": "TOWER"}]
+	}`
+	match, fixes, err := importMatch([]byte(data))
+	require.NoError(t, err)
+	assert.Equal(t, matchpb.MapType_MOUNTAIN, match.GetMap().GetType())
+	assert.Contains(t, fixes, "no map, inferred Mountain from its landmarks")
+}
+
+func TestImportMatchUnchanged(t *testing.T) {
+	data := `{"Players": [{"Type": "CORVID", "Name": "Corvid Conspiracy"}], "Map": {"Type": "LAKE", "Name": "Lake"}}`
+	_, fixes, err := importMatch([]byte(data))
+	require.NoError(t, err)
+	assert.Empty(t, fixes)
+}
+
+func TestImportDir(t *testing.T) {
+	dir := t.TempDir()
+	for name, data := range map[string]string{
+		"a.json": `{"players": [{"Type": "RIVERFOLK"}]}`,
+		"b.json": `{"Players": [{"Type": "CORVID"}], "Bots": [{"Type": "CORVID"}]}`,
+		"c.json": `not json`,
+		"d.json": `{"Players": [{"Type": "MARQUISE"}], "Bots": [{"Type": "EYRIE", "Name": "Electric Eyrie"}]}`,
+	} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(data), 0o644))
+	}
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.jsonl"))
+	require.NoError(t, err)
+
+	results, err := importDir(dir, history)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.NoError(t, results[0].Err)
+	assert.ErrorAs(t, results[1].Err, &ValidationError{})
+	assert.Error(t, results[2].Err)
+	// Named bots get the Clockwork bot they didn't record.
+	require.NoError(t, results[3].Err)
+	assert.Contains(t, results[3].Fixes, "set Bots[0] to ELECTRIC_EYRIE")
+
+	require.Len(t, history.Records(), 2)
+	assert.Equal(t, results[0].Id, history.Records()[0].GetId())
+	assert.Equal(t, RiverfolkCompany, history.Records()[0].GetMatch().GetPlayers()[0].GetName())
+	assert.Equal(t, matchpb.BotType_ELECTRIC_EYRIE, history.Records()[1].GetMatch().GetBots()[0].GetClockwork())
+
+	// Importing the directory again skips what it already imported.
+	again, err := importDir(dir, history)
+	require.NoError(t, err)
+	assert.True(t, again[0].Skipped)
+	assert.Equal(t, results[0].Id, again[0].Id)
+	assert.True(t, again[3].Skipped)
+	assert.Len(t, history.Records(), 2)
+
+	// The command fails when any file did.
+	err = runCLI([]string{"import", "-history", filepath.Join(t.TempDir(), "history.pb"), dir}, io.Discard)
+	assert.ErrorContains(t, err, "2 of 4 files failed to import")
+}
@@ -1,17 +1,20 @@
 package main
 
 import (
+	"cmp"
 	"fmt"
+	"maps"
 	"math/rand"
 	"os"
+	"slices"
 
+	"LegacyRoot/board"
 	"LegacyRoot/matchpb"
 
-	"github.com/a-h/templ"
-	"github.com/labstack/echo"
-	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
 )
 
+// Faction ids match matchpb.FactionType.
 const (
 	Marquise int32 = iota
 	Eyrie
@@ -19,7 +22,7 @@ const (
 	Vagabond
 	Riverfolk
 	Lizard
-	Underground
+	Underground int32 = iota + 2
 	Corvid
 	Hundreds
 	Keepers
@@ -41,15 +44,20 @@ const (
 	Mountain
 )
 
+// Landmark ids match matchpb.LandmarkType.
 const (
 	Tower int32 = iota
 	Ferry
 	City
-	Treetop
 	Forge
+	Treetop
 	Market
 )
 
+var MapNames = map[int32]string{Autumn: "Autumn", Winter: "Winter", Lake: "Lake", Mountain: "Mountain"}
+
+var Landmarks = []int32{Tower, Ferry, Treetop, City, Market, Forge}
+
 const (
 	MarquiseDeCat     = "Marquise de Cat"
 	EyrieDynasties    = "Eyrie Dynasties"
@@ -125,6 +133,86 @@ var FactionNames = map[int32]string{
 	Keepers:     KeepersInIron,
 }
 
+// FactionClass tells militant factions, who fight for the board, from
+// insurgent ones, who work their way in from the edges.
+type FactionClass int
+
+const (
+	Militant FactionClass = iota
+	Insurgent
+)
+
+var FactionClasses = map[int32]FactionClass{
+	Marquise:    Militant,
+	Eyrie:       Militant,
+	Alliance:    Insurgent,
+	Vagabond:    Insurgent,
+	Riverfolk:   Insurgent,
+	Lizard:      Insurgent,
+	Underground: Militant,
+	Corvid:      Insurgent,
+	Hundreds:    Militant,
+	Keepers:     Militant,
+}
+
+// factionsOfClass lists the factions of class in id order.
+func factionsOfClass(class FactionClass) []matchpb.FactionType {
+	factions := []matchpb.FactionType{}
+	for _, f := range sortedKeys(FactionClasses) {
+		if FactionClasses[f] == class {
+			factions = append(factions, matchpb.FactionType(f))
+		}
+	}
+	return factions
+}
+
+type BotEntry struct {
+	Name string
+	// Mirrors is the faction the bot plays, a bot can't share the table with
+	// a player or hireling of the same faction.
+	Mirrors int32
+	Traits  []string
+}
+
+// Clockwork bots along with the optional traits each bot can be given.
+var BotCatalog = map[matchpb.BotType]BotEntry{
+	matchpb.BotType_MECHANICAL_MARQUISE: {
+		"Mechanical Marquise 2.0", Marquise, []string{"Blitz", "Fortified", "Hospitals", "Iron Will"},
+	},
+	matchpb.BotType_ELECTRIC_EYRIE: {
+		"Electric Eyrie", Eyrie, []string{"Nobility", "Relentless", "Swoop", "War Tax"},
+	},
+	matchpb.BotType_AUTOMATED_ALLIANCE: {
+		"Automated Alliance", Alliance, []string{"Informants", "Popularity", "Veterans", "Wildfire"},
+	},
+	matchpb.BotType_VAGABOT: {
+		"Vagabot", Vagabond, []string{"Adventurer", "Berserker", "Helper", "Marksman"},
+	},
+	matchpb.BotType_RIVERFOLK_ROBOTS: {
+		"Riverfolk Robots", Riverfolk, []string{"Ferocious", "Greedy", "Riverboats", "Swindler"},
+	},
+	matchpb.BotType_LOGICAL_LIZARDS: {
+		"Logical Lizards", Lizard, []string{"Erratic", "Fanatics", "Martyrs", "Spiteful"},
+	},
+	matchpb.BotType_DRILLBIT_DUCHY: {
+		"Drillbit Duchy", Underground, []string{"Foundations", "Invaders", "Investors", "Overwhelm"},
+	},
+	matchpb.BotType_COGWHEEL_CORVIDS: {
+		"Cogwheel Corvids", Corvid, []string{"Disguise", "Instigators", "Spymaster", "Venomous"},
+	},
+}
+
+// mirroringBot is the Clockwork bot that mirrors faction, which bots of
+// older matches that only recorded their faction play.
+func mirroringBot(faction matchpb.FactionType) matchpb.BotType {
+	for _, b := range sortedKeys(BotCatalog) {
+		if BotCatalog[b].Mirrors == int32(faction) {
+			return b
+		}
+	}
+	return matchpb.BotType_BOT_TYPE_UNSPECIFIED
+}
+
 var Hirelings = map[int32][]string{
 	Marquise:    {"Forest Patrol", "Feline Physicians"},
 	Eyrie:       {"Last Dynasties", "Bluebird Nobles"},
@@ -148,14 +236,14 @@ type Item struct {
 }
 
 // Function to choose an item randomly based on the given probabilities
-func pickRandom(items []Item) int32 {
+func pickRandom(rng *rand.Rand, items []Item) int32 {
 	// Calculate the total weight
 	totalWeight := 0.0
 	for _, item := range items {
 		totalWeight += item.Weight
 	}
 
-	random := rand.Float64() * totalWeight
+	random := rng.Float64() * totalWeight
 
 	// Select the item based on cumulative weight
 	cumulativeWeight := 0.0
@@ -176,14 +264,73 @@ func NewFaction(enum int32) *matchpb.Faction {
 }
 
 type MatchCfg struct {
-	UseHirelings bool
-	UseLandmarks bool
-	BotEnemies   int32
-	Players      int32
+	UseHirelings  bool
+	UseLandmarks  bool
+	BotEnemies    int32
+	Players       int32
+	MinDifficulty matchpb.BotDifficulty
+	MaxDifficulty matchpb.BotDifficulty
+	// TargetChallenge is the sum of the bot difficulty levels (Easy being 0),
+	// spread across the bots within the difficulty range. 0 rolls each bot on
+	// its own instead.
+	TargetChallenge int32
+	MaxBotTraits    int32
+	// MinScore and MaxScore bound the estimated match difficulty, a MaxScore
+	// of 0 accepts any match.
+	MinScore float64
+	MaxScore float64
+	// Pairings controls bots whose matchup score against a player reaches
+	// BadPairing.
+	Pairings   PairingMode
+	BadPairing float64
+	// Setup picks the setup rules, see generateAdsetMatch for ADSET.
+	Setup SetupMode
+	// UseExilesDeck lets the Exiles & Partisans deck be picked, the base deck
+	// always can be.
+	UseExilesDeck bool
+	// SetupOrder is the order seats set up in.
+	SetupOrder SetupOrder
+	// Coverage strongly prefers factions, bots, hirelings, maps and
+	// landmarks CoveragePlayer hasn't played yet, the whole group's when
+	// CoveragePlayer is empty. Covered is filled in from the history and
+	// stored with the config.
+	Coverage       bool
+	CoveragePlayer string
+	Covered        Coverage
+	// Factions, when set, are the players' factions instead of rolled ones.
+	// ADSET matches draft theirs regardless.
+	Factions []matchpb.FactionType
+}
+
+// defaultMatchCfg is the config used when nothing else is asked for.
+func defaultMatchCfg() MatchCfg {
+	return MatchCfg{
+		UseHirelings:  true,
+		UseLandmarks:  true,
+		UseExilesDeck: true,
+		Players:       1,
+		BotEnemies:    1,
+		MinDifficulty: matchpb.BotDifficulty_EASY,
+		MaxDifficulty: matchpb.BotDifficulty_NIGHTMARE,
+		MaxBotTraits:  2,
+		Pairings:      DownWeightPairings,
+		BadPairing:    0.5,
+	}
+}
+
+func randomBetween(rng *rand.Rand, min, max int32) int32 {
+	return min + int32(rng.Intn(int(max-min+1))) // Generate random number in range [min, max]
 }
 
-func randomBetween(min, max int32) int32 {
-	return int32(rand.Intn(int(max - min + 1 + min))) // Generate random number in range [min, max]
+// sortedKeys lets picks go over maps in a stable order, so the same seed
+// always generates the same match.
+func sortedKeys[K cmp.Ordered, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
 }
 
 func removeFromPool(e int32, pool []Item) []Item {
@@ -197,23 +344,61 @@ func removeFromPool(e int32, pool []Item) []Item {
 }
 
 func generateNewMatch(
+	rng *rand.Rand,
 	prev *matchpb.Match,
 	factions map[int32]string,
-	bots map[int32]string,
+	bots map[matchpb.BotType]BotEntry,
 	hirelings map[int32][]string,
 	cfg *MatchCfg,
-) *matchpb.Match {
+) (*matchpb.Match, error) {
 	newMatch := &matchpb.Match{}
 
 	// Pick player factions.
-	newMatch.Players = []*matchpb.Faction{pickPlayerFactions(prev, factions)}
+	if len(cfg.Factions) > 0 {
+		for _, f := range cfg.Factions {
+			newMatch.Players = append(newMatch.Players, NewFaction(int32(f)))
+		}
+	} else {
+		players, err := pickPlayers(rng, prev, factions, cfg.Players, cfg.Covered.Factions)
+		if err != nil {
+			return nil, err
+		}
+		newMatch.Players = players
+	}
+
+	if err := completeMatch(rng, prev, newMatch, bots, hirelings, cfg); err != nil {
+		return nil, err
+	}
+	return newMatch, nil
+}
 
+// completeMatch picks everything but the players of a match whose players
+// are already set, keeping their factions out of the bot and hireling pools.
+// It fails when fewer bots than cfg asks for can face the players.
+func completeMatch(
+	rng *rand.Rand,
+	prev *matchpb.Match,
+	newMatch *matchpb.Match,
+	bots map[matchpb.BotType]BotEntry,
+	hirelings map[int32][]string,
+	cfg *MatchCfg,
+) error {
 	// Remove player factions from bot and hirelings pools.
-	delete(hirelings, int32(newMatch.GetPlayers()[0].GetType()))
-	delete(bots, int32(newMatch.GetPlayers()[0].GetType()))
+	for _, player := range newMatch.GetPlayers() {
+		delete(hirelings, int32(player.GetType()))
+		for b, bot := range bots {
+			if bot.Mirrors == int32(player.GetType()) {
+				delete(bots, b)
+			}
+		}
+	}
 
 	// Pick Bots
-	newMatch.Bots = pickBotFactions(prev, cfg.BotEnemies, bots)
+	var err error
+	newMatch.Bots, err = pickBotFactions(rng, prev, cfg, bots, newMatch.GetPlayers())
+	if err != nil {
+		return err
+	}
 
 	// Remove non compatible hirelings based on bot factions.
 	for _, bot := range newMatch.GetBots() {
@@ -221,61 +406,184 @@ func generateNewMatch(
 	}
 
 	// Pick hireings.
-	newMatch.Hirelings = pickHirelings(prev, hirelings)
+	if cfg.UseHirelings {
+		newMatch.Hirelings = pickHirelings(rng, prev, hirelings, tableSize(newMatch), cfg.Covered.Hirelings)
+	}
 
 	// Pick Map
-	maps := map[int32]string{Autumn: "Autumn", Winter: "Winter", Lake: "Lake", Mountain: "Mountain"}
-	newMatch.Map = pickMap(prev, maps)
+	newMatch.Map = pickMap(rng, prev, MapNames, cfg.Covered.Maps)
 
 	// Pick Landmarks
-	nLandmarks := randomBetween(0, 3)
-	landmarks := []int32{Tower, Ferry, Treetop, City, Market, Forge}
-	newMatch.Landmarks = pickLandmarks(nLandmarks, landmarks)
+	if cfg.UseLandmarks {
+		nLandmarks := randomBetween(rng, 0, 3)
+		newMatch.Landmarks = pickLandmarks(rng, nLandmarks, Landmarks, cfg.Covered.Landmarks)
+	}
+
+	// Pick Deck
+	newMatch.Deck = pickDeck(rng, prev, cfg)
+
+	// Seat the table
+	pickSeats(rng, newMatch, cfg.SetupOrder)
+	newMatch.Homes = board.Homes(newMatch)
+	return nil
+}
+
+// rerollComponent picks one component of a match again, keeping the rest of
+// the match as it is. Factions already at the table stay out of the pools.
+// New players or bots seat the table again, and the homes are set up again
+// for the new table or map.
+func rerollComponent(rng *rand.Rand, match *matchpb.Match, component matchpb.Component, cfg *MatchCfg) (*matchpb.Match, error) {
+	if cfg.Setup == AdsetSetup {
+		return rerollAdsetComponent(rng, match, component, cfg), nil
+	}
+	rerolled := proto.Clone(match).(*matchpb.Match)
+	inPlay := map[int32]bool{}
+	if component != matchpb.Component_PLAYERS {
+		for _, player := range match.GetPlayers() {
+			inPlay[int32(player.GetType())] = true
+		}
+	}
+	if component != matchpb.Component_BOTS {
+		for _, bot := range match.GetBots() {
+			inPlay[int32(bot.GetType())] = true
+		}
+	}
+	if component != matchpb.Component_HIRELINGS {
+		for _, hireling := range match.GetHirelings() {
+			inPlay[int32(hireling.GetType())] = true
+		}
+	}
 
-	return newMatch
+	switch component {
+	case matchpb.Component_PLAYERS:
+		factions := maps.Clone(FactionNames)
+		maps.DeleteFunc(factions, func(f int32, _ string) bool { return inPlay[f] })
+		players, err := pickPlayers(rng, match, factions, cfg.Players, cfg.Covered.Factions)
+		if err != nil {
+			return nil, err
+		}
+		rerolled.Players = players
+	case matchpb.Component_BOTS:
+		bots := maps.Clone(BotCatalog)
+		maps.DeleteFunc(bots, func(_ matchpb.BotType, bot BotEntry) bool { return inPlay[bot.Mirrors] })
+		var err error
+		if rerolled.Bots, err = pickBotFactions(rng, match, cfg, bots, rerolled.GetPlayers()); err != nil {
+			return nil, err
+		}
+	case matchpb.Component_HIRELINGS:
+		hirelings := maps.Clone(Hirelings)
+		maps.DeleteFunc(hirelings, func(f int32, _ []string) bool { return inPlay[f] })
+		rerolled.Hirelings = pickHirelings(rng, match, hirelings, tableSize(rerolled), cfg.Covered.Hirelings)
+	case matchpb.Component_MAP:
+		rerolled.Map = pickMap(rng, match, MapNames, cfg.Covered.Maps)
+	case matchpb.Component_LANDMARKS:
+		rerolled.Landmarks = pickLandmarks(rng, randomBetween(rng, 0, 3), Landmarks, cfg.Covered.Landmarks)
+	case matchpb.Component_DECK:
+		rerolled.Deck = pickDeck(rng, match, cfg)
+	}
+	switch component {
+	case matchpb.Component_PLAYERS, matchpb.Component_BOTS, matchpb.Component_SEATS:
+		pickSeats(rng, rerolled, cfg.SetupOrder)
+	}
+	if demotedHirelings(tableSize(rerolled)) != demotedHirelings(tableSize(match)) {
+		demoteHirelings(rng, rerolled.GetHirelings(), tableSize(rerolled))
+	}
+	rerolled.Homes = board.Homes(rerolled)
+	return rerolled, nil
 }
 
-func pickLandmarks(n int32, landmarks []int32) []*matchpb.Landmark {
-	pickedLandmarks := []*matchpb.Landmark{{}, {}, {}}
+func pickLandmarks(rng *rand.Rand, n int32, landmarks []int32, covered map[int32]bool) []*matchpb.Landmark {
+	pickedLandmarks := []*matchpb.Landmark{}
 	if n > 0 {
 		landmarkSelection := []Item{}
 		for _, v := range landmarks {
 			landmarkSelection = append(landmarkSelection, Item{Name: v, Weight: 1.0 / float64(len(landmarks))})
 		}
+		landmarkSelection = preferUncovered(landmarkSelection, covered)
 
-		for i := range n {
-			landmarkId := pickRandom(landmarkSelection)
-			pickedLandmarks[i] = &matchpb.Landmark{
+		for range n {
+			landmarkId := pickRandom(rng, landmarkSelection)
+			pickedLandmarks = append(pickedLandmarks, &matchpb.Landmark{
 				Type: matchpb.LandmarkType(landmarkId),
 				Name: getLandmarkName(landmarkId),
-			}
+			})
 			landmarkSelection = removeFromPool(landmarkId, landmarkSelection)
 		}
 	}
 	return pickedLandmarks
 }
 
-func pickMap(prev *matchpb.Match, maps map[int32]string) *matchpb.MapVal {
+// pickDeck picks the deck out of those cfg lets in, the previous match's
+// deck being the likelier one like its map.
+func pickDeck(rng *rand.Rand, prev *matchpb.Match, cfg *MatchCfg) matchpb.Deck {
+	decks := []matchpb.Deck{matchpb.Deck_BASE_DECK}
+	if cfg.UseExilesDeck {
+		decks = append(decks, matchpb.Deck_EXILES_AND_PARTISANS)
+	}
+	deckSelection := []Item{}
+	for _, d := range decks {
+		if d == prev.GetDeck() {
+			deckSelection = append(deckSelection, Item{Name: int32(d), Weight: 0.6})
+		} else {
+			deckSelection = append(deckSelection, Item{Name: int32(d), Weight: 0.4})
+		}
+	}
+	return matchpb.Deck(pickRandom(rng, deckSelection))
+}
+
+func pickMap(rng *rand.Rand, prev *matchpb.Match, maps map[int32]string, covered map[int32]bool) *matchpb.MapVal {
 	mapSelection := []Item{}
-	for k := range maps {
+	for _, k := range sortedKeys(maps) {
 		if k == int32(prev.Map.GetType()) {
 			mapSelection = append(mapSelection, Item{Name: k, Weight: 0.34})
 		} else {
 			mapSelection = append(mapSelection, Item{Name: k, Weight: 0.22})
 		}
 	}
-	m := pickRandom(mapSelection)
+	m := pickRandom(rng, preferUncovered(mapSelection, covered))
 
 	return &matchpb.MapVal{Type: matchpb.MapType(m), Name: maps[m]}
 }
 
-func pickHirelings(prev *matchpb.Match, hirelings map[int32][]string) []*matchpb.Faction {
-	nHirelings := randomBetween(0, 3)
-	pickedHirelings := []*matchpb.Faction{{}, {}, {}}
+// Hireling control passes to the lowest scoring player once anyone reaches
+// the threshold the hireling was set up at.
+var HirelingThresholds = []int32{4, 8, 12}
+
+// demotedHirelings returns how many hirelings start on their demoted side,
+// one for every seat past the second.
+func demotedHirelings(seats int32) int32 {
+	return max(0, min(seats-2, int32(len(HirelingThresholds))))
+}
+
+// tableSize is how many seats the match's players and bots take.
+func tableSize(match *matchpb.Match) int32 {
+	return int32(len(match.GetPlayers()) + len(match.GetBots()))
+}
+
+// demoteHirelings turns as many of the hirelings to their demoted side as a
+// table of seats calls for, which ones being up to the table and so picked
+// at random.
+func demoteHirelings(rng *rand.Rand, hirelings []*matchpb.Hireling, seats int32) {
+	demoted := demotedHirelings(seats)
+	for i, j := range rng.Perm(len(hirelings)) {
+		h := hirelings[j]
+		h.Status = matchpb.HirelingStatus_PROMOTED
+		if int32(i) < demoted {
+			h.Status = matchpb.HirelingStatus_DEMOTED
+		}
+		if names, ok := Hirelings[int32(h.GetType())]; ok {
+			h.Name = names[h.GetStatus()]
+		}
+	}
+}
+
+func pickHirelings(rng *rand.Rand, prev *matchpb.Match, hirelings map[int32][]string, seats int32, covered map[int32]bool) []*matchpb.Hireling {
+	nHirelings := randomBetween(rng, 0, 3)
+	pickedHirelings := []*matchpb.Hireling{}
 	if nHirelings > 0 {
 		hirelingFactions := []Item{}
 		prevCount := 0
-		for k := range hirelings {
+		for _, k := range sortedKeys(hirelings) {
 			for _, prevHireling := range prev.Hirelings {
 				if int32(prevHireling.GetType()) == k {
 					prevCount += 1
@@ -285,122 +593,215 @@ func pickHirelings(prev *matchpb.Match, hirelings map[int32][]string) []*matchpb
 		}
 
 		weightAll := 1.0
-		for k := range hirelings {
+		for _, k := range sortedKeys(hirelings) {
 			hirelingFactions = append(
 				hirelingFactions,
 				Item{Name: k, Weight: float64((weightAll - (0.15 * float64(prevCount))) / 10)},
 			)
 		}
 
+		hirelingFactions = preferUncovered(hirelingFactions, covered)
 		for i := range nHirelings {
-			rank := randomBetween(0, 1)
-			h := pickRandom(hirelingFactions)
-			pickedHirelings[i] = &matchpb.Faction{Type: matchpb.FactionType(h), Name: hirelings[h][rank]}
-			for j, h := range hirelingFactions {
-				if h.Name == int32(pickedHirelings[i].Type) {
+			h := pickRandom(rng, hirelingFactions)
+			pickedHirelings = append(pickedHirelings, &matchpb.Hireling{
+				Type:      matchpb.FactionType(h),
+				Threshold: HirelingThresholds[i],
+			})
+			for j := len(hirelingFactions) - 1; j >= 0; j-- {
+				if hirelingFactions[j].Name == h {
 					hirelingFactions = append(hirelingFactions[:j], hirelingFactions[j+1:]...)
 				}
 			}
 		}
+
+		demoteHirelings(rng, pickedHirelings, seats)
 	}
 	return pickedHirelings
 }
 
-func pickPlayerFactions(prev *matchpb.Match, factions map[int32]string) *matchpb.Faction {
+// pickPlayers picks n distinct player factions out of factions, at least
+// one, taking them out of the pool.
+func pickPlayers(rng *rand.Rand, prev *matchpb.Match, factions map[int32]string, n int32, covered map[int32]bool) ([]*matchpb.Faction, error) {
+	n = max(n, 1)
+	if len(factions) < int(n) {
+		return nil, fmt.Errorf("only %d factions are left for %d players", len(factions), n)
+	}
+	players := []*matchpb.Faction{}
+	for range n {
+		player := pickPlayerFactions(rng, prev, factions, covered)
+		delete(factions, int32(player.GetType()))
+		players = append(players, player)
+	}
+	return players, nil
+}
+
+func pickPlayerFactions(rng *rand.Rand, prev *matchpb.Match, factions map[int32]string, covered map[int32]bool) *matchpb.Faction {
 	playerFactions := []Item{}
-	for f := range factions {
-		if f == int32(prev.Players[0].GetType()) {
+	for _, f := range sortedKeys(factions) {
+		if slices.ContainsFunc(prev.GetPlayers(), func(p *matchpb.Faction) bool { return int32(p.GetType()) == f }) {
 			playerFactions = append(playerFactions, Item{Name: f, Weight: 0.28})
 		} else {
 			playerFactions = append(playerFactions, Item{Name: f, Weight: 0.08})
 		}
 	}
-	factionId := pickRandom(playerFactions)
+	factionId := pickRandom(rng, preferUncovered(playerFactions, covered))
 	playerFaction := NewFaction(factionId)
 	return playerFaction
 }
 
-func pickBotFactions(prev *matchpb.Match, n int32, factions map[int32]string) []*matchpb.Faction {
-	BotFactions := []Item{}
-	for f := range factions {
+func pickBotFactions(
+	rng *rand.Rand,
+	prev *matchpb.Match,
+	cfg *MatchCfg,
+	catalog map[matchpb.BotType]BotEntry,
+	players []*matchpb.Faction,
+) ([]*matchpb.Bot, error) {
+	botFactions := []Item{}
+	for _, b := range sortedKeys(catalog) {
+		bot := catalog[b]
+		weight := 0.1
+		// Older matches only recorded the faction a bot mirrors.
 		for _, prevBot := range prev.Bots {
-			if f == int32(prevBot.GetType()) {
-				BotFactions = append(BotFactions, Item{Name: f, Weight: 0.15})
-				break
-			} else {
-				BotFactions = append(BotFactions, Item{Name: f, Weight: 0.1})
+			if bot.Mirrors == int32(prevBot.GetType()) {
+				weight = 0.15
 				break
 			}
 		}
+		weight *= pairingWeight(players, bot.Mirrors, cfg)
+		if weight > 0 {
+			botFactions = append(botFactions, Item{Name: int32(b), Weight: weight})
+		}
+	}
+	botFactions = preferUncovered(botFactions, cfg.Covered.Bots)
+	if len(botFactions) < int(cfg.BotEnemies) {
+		return nil, fmt.Errorf("only %d of the %d bots asked for can face the players", len(botFactions), cfg.BotEnemies)
 	}
-	bots := []*matchpb.Faction{{}, {}}
+	n := cfg.BotEnemies
+	difficulties := pickBotDifficulties(rng, n, cfg)
+	bots := []*matchpb.Bot{}
 	for i := range n {
-		botId := int32(pickRandom(BotFactions))
-		bots[i] = NewFaction(botId)
-		removeFromPool(botId, BotFactions)
+		botId := pickRandom(rng, botFactions)
+		bot := catalog[matchpb.BotType(botId)]
+		bots = append(bots, &matchpb.Bot{
+			Type:       matchpb.FactionType(bot.Mirrors),
+			Name:       bot.Name,
+			Difficulty: difficulties[i],
+			Traits:     pickBotTraits(rng, bot.Traits, cfg.MaxBotTraits),
+			Clockwork:  matchpb.BotType(botId),
+		})
+		botFactions = removeFromPool(botId, botFactions)
 	}
-	return bots
+	return bots, nil
 }
 
-/*
-func main() {
-	fmt.Println("Running")
-	//var hFlag = flag.Bool("h", true, "Use hirelings")
-	playerFactions := map[int32]string{Marquise: MarquiseDeCat,
-		Eyrie:       EyrieDynasties,
-		Alliance:    WoodlandAlliance,
-		Vagabond:    TheVagabond,
-		Riverfolk:   RiverfolkCompany,
-		Lizard:      LizardCult,
-		Underground: UndergroundDuchy,
-		Corvid:      CorvidConspiracy,
-		Hundreds:    LordOfTheHundreds,
-		Keepers:     KeepersInIron,
-	}
-
-	BotFactions := map[int32]string{Marquise: MarquiseDeCat,
-		Eyrie:       EyrieDynasties,
-		Alliance:    WoodlandAlliance,
-		Vagabond:    TheVagabond,
-		Riverfolk:   RiverfolkCompany,
-		Lizard:      LizardCult,
-		Underground: UndergroundDuchy,
-		Corvid:      CorvidConspiracy,
-	}
-
-	previous, err := parseMatch("match.json")
-	if err != nil {
-		fmt.Printf("failed to parse previous match: %v", err)
-		return
+// pickBotDifficulties rolls a difficulty for each of the n bots. With a target
+// challenge every bot starts at the minimum difficulty and levels are handed
+// out at random until the target is met or every bot is at the maximum.
+func pickBotDifficulties(rng *rand.Rand, n int32, cfg *MatchCfg) []matchpb.BotDifficulty {
+	lo, hi := cfg.MinDifficulty, max(cfg.MinDifficulty, cfg.MaxDifficulty)
+	difficulties := make([]matchpb.BotDifficulty, n)
+	if cfg.TargetChallenge <= 0 {
+		for i := range difficulties {
+			difficulties[i] = matchpb.BotDifficulty(randomBetween(rng, int32(lo), int32(hi)))
+		}
+		return difficulties
 	}
 
-	cfg := MatchCfg{UseHirelings: false, UseLandmarks: true, Players: 1, BotEnemies: 1}
-	newMatch := generateNewMatch(previous, playerFactions, BotFactions, Hirelings, &cfg)
-	fmt.Printf("Player Faction: %v\n", newMatch.GetPlayers()[0].Name)
-	fmt.Printf("Enemies: %v %v\n", newMatch.GetBots()[0].Name, newMatch.GetBots()[1].Name)
-	fmt.Printf("Hirelings: ")
-	for i := range newMatch.Hirelings {
-		fmt.Printf("%v ", newMatch.Hirelings[i].Name)
+	challenge := int32(0)
+	for i := range difficulties {
+		difficulties[i] = lo
+		challenge += int32(lo)
 	}
-	fmt.Println("")
-	fmt.Printf("Map: %v\n", newMatch.Map.Name)
-	fmt.Printf("Landmarks: ")
-	for i := range newMatch.Landmarks {
-		fmt.Printf("%v ", newMatch.GetLandmarks()[i].Name)
+	for challenge < cfg.TargetChallenge {
+		open := []int{}
+		for i, d := range difficulties {
+			if d < hi {
+				open = append(open, i)
+			}
+		}
+		if len(open) == 0 {
+			break
+		}
+		difficulties[open[rng.Intn(len(open))]]++
+		challenge++
 	}
-	fmt.Println("")
-}*/
+	return difficulties
+}
+
+func pickBotTraits(rng *rand.Rand, traits []string, maxTraits int32) []string {
+	n := randomBetween(rng, 0, min(maxTraits, int32(len(traits))))
+	picked := []string{}
+	for _, i := range rng.Perm(len(traits))[:n] {
+		picked = append(picked, traits[i])
+	}
+	return picked
+}
 
 func main() {
-	e := echo.New()
-	e.GET("/", func(c echo.Context) error {
-		return render(c, hello("John"))
-	})
-	e.Logger.Fatal(e.Start(":1323"))
+	if err := runCLI(os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
 }
 
-func render(ctx echo.Context, cmp templ.Component) error {
-	return cmp.Render(ctx.Request().Context(), ctx.Response())
+// previousMatch is the match new matches are weighted against, the last one
+// in the history or match.json before anything was generated.
+func previousMatch(history *History) (*matchpb.Match, error) {
+	if prev := history.Last(); prev != nil {
+		return prev, nil
+	}
+	if _, err := os.Stat("match.json"); err != nil {
+		return &matchpb.Match{}, nil
+	}
+	return parseMatch("match.json")
+}
+
+func matchCfgFromProto(cfg *matchpb.MatchConfig) *MatchCfg {
+	return &MatchCfg{
+		UseHirelings:    cfg.GetUseHirelings(),
+		UseLandmarks:    cfg.GetUseLandmarks(),
+		BotEnemies:      cfg.GetBotEnemies(),
+		Players:         cfg.GetPlayers(),
+		MinDifficulty:   cfg.GetMinDifficulty(),
+		MaxDifficulty:   cfg.GetMaxDifficulty(),
+		TargetChallenge: cfg.GetTargetChallenge(),
+		MaxBotTraits:    cfg.GetMaxBotTraits(),
+		MinScore:        cfg.GetMinScore(),
+		MaxScore:        cfg.GetMaxScore(),
+		Pairings:        PairingMode(cfg.GetPairings()),
+		BadPairing:      cfg.GetBadPairing(),
+		Setup:           SetupMode(cfg.GetSetup()),
+		UseExilesDeck:   cfg.GetUseExilesDeck(),
+		SetupOrder:      SetupOrder(cfg.GetSetupOrder()),
+		Coverage:        cfg.GetCoverage(),
+		CoveragePlayer:  cfg.GetCoveragePlayer(),
+		Factions:        cfg.GetFactions(),
+		Covered:         coverageFromProto(cfg.GetCovered()),
+	}
+}
+
+func matchCfgToProto(cfg *MatchCfg) *matchpb.MatchConfig {
+	return &matchpb.MatchConfig{
+		UseHirelings:    cfg.UseHirelings,
+		UseLandmarks:    cfg.UseLandmarks,
+		BotEnemies:      cfg.BotEnemies,
+		Players:         cfg.Players,
+		MinDifficulty:   cfg.MinDifficulty,
+		MaxDifficulty:   cfg.MaxDifficulty,
+		TargetChallenge: cfg.TargetChallenge,
+		MaxBotTraits:    cfg.MaxBotTraits,
+		MinScore:        cfg.MinScore,
+		MaxScore:        cfg.MaxScore,
+		Pairings:        matchpb.PairingMode(cfg.Pairings),
+		BadPairing:      cfg.BadPairing,
+		Setup:           matchpb.SetupMode(cfg.Setup),
+		UseExilesDeck:   cfg.UseExilesDeck,
+		SetupOrder:      matchpb.SetupOrder(cfg.SetupOrder),
+		Coverage:        cfg.Coverage,
+		CoveragePlayer:  cfg.CoveragePlayer,
+		Factions:        cfg.Factions,
+		Covered:         coverageToProto(cfg.Covered),
+	}
 }
 
 func parseMatch(filename string) (*matchpb.Match, error) {
@@ -409,10 +810,12 @@ func parseMatch(filename string) (*matchpb.Match, error) {
 		return nil, fmt.Errorf("failed to read match file: %w", err)
 	}
 
-	match := &matchpb.Match{}
-	err = protojson.Unmarshal(data, match)
+	match, _, err := importMatch(data)
 	if err != nil {
-		return nil, fmt.Errorf("failed to deserialize match: %w", err)
+		return nil, err
+	}
+	if violations := Validate(match); len(violations) > 0 {
+		return nil, ValidationError(violations)
 	}
 
 	return match, nil
@@ -2,11 +2,18 @@ package main
 
 import (
 	"LegacyRoot/matchpb"
+	"maps"
+	"math/rand"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+// Source for the pickers under test.
+var rng = rand.New(rand.NewSource(time.Now().UnixNano()))
+
 func TestParseMatchJSON(t *testing.T) {
 	match, err := parseMatch("test_match_0.json")
 	assert.NoError(t, err)
@@ -23,3 +30,153 @@ func TestParseMatchJSON(t *testing.T) {
 	assert.Equal(t, match.GetMap().GetType(), matchpb.MapType_AUTUMN)
 	assert.Equal(t, match.GetLandmarks()[0].GetType(), matchpb.LandmarkType_FORGE)
 }
+
+func TestPickHirelingsThresholds(t *testing.T) {
+	prev := &matchpb.Match{}
+	for range 20 {
+		hirelings := pickHirelings(rng, prev, maps.Clone(Hirelings), 4, nil)
+		demoted := 0
+		for i, h := range hirelings {
+			assert.Equal(t, HirelingThresholds[i], h.GetThreshold())
+			assert.Equal(t, Hirelings[int32(h.GetType())][h.GetStatus()], h.GetName())
+			if h.GetStatus() == matchpb.HirelingStatus_DEMOTED {
+				demoted++
+			}
+		}
+		assert.Equal(t, min(2, len(hirelings)), demoted)
+	}
+}
+
+func countDemoted(hirelings []*matchpb.Hireling) int {
+	demoted := 0
+	for _, h := range hirelings {
+		if h.GetStatus() == matchpb.HirelingStatus_DEMOTED {
+			demoted++
+		}
+	}
+	return demoted
+}
+
+func TestHirelingsDemotedForTable(t *testing.T) {
+	cfg := defaultMatchCfg()
+	cfg.Players, cfg.BotEnemies = 3, 2
+	for range 30 {
+		match, err := generateNewMatch(rng, &matchpb.Match{}, maps.Clone(FactionNames), maps.Clone(BotCatalog), maps.Clone(Hirelings), &cfg)
+		require.NoError(t, err)
+		// The hirelings go by the seats the match has, not the ones asked for.
+		assert.Equal(t, min(len(match.GetHirelings()), int(demotedHirelings(tableSize(match)))), countDemoted(match.GetHirelings()))
+
+		noBots := cfg
+		noBots.BotEnemies = 0
+		rerolled, err := rerollComponent(rng, match, matchpb.Component_BOTS, &noBots)
+		require.NoError(t, err)
+		assert.Equal(t, min(len(rerolled.GetHirelings()), int(demotedHirelings(tableSize(rerolled)))), countDemoted(rerolled.GetHirelings()))
+		for _, h := range rerolled.GetHirelings() {
+			assert.Equal(t, Hirelings[int32(h.GetType())][h.GetStatus()], h.GetName())
+		}
+	}
+}
+
+func TestPickBotDifficultiesRange(t *testing.T) {
+	cfg := &MatchCfg{MinDifficulty: matchpb.BotDifficulty_DEFAULT, MaxDifficulty: matchpb.BotDifficulty_CHALLENGING}
+	for range 20 {
+		for _, d := range pickBotDifficulties(rng, 2, cfg) {
+			assert.GreaterOrEqual(t, d, matchpb.BotDifficulty_DEFAULT)
+			assert.LessOrEqual(t, d, matchpb.BotDifficulty_CHALLENGING)
+		}
+	}
+}
+
+func TestPickBotDifficultiesTarget(t *testing.T) {
+	cfg := &MatchCfg{
+		MinDifficulty:   matchpb.BotDifficulty_EASY,
+		MaxDifficulty:   matchpb.BotDifficulty_NIGHTMARE,
+		TargetChallenge: 4,
+	}
+	for range 20 {
+		challenge := int32(0)
+		for _, d := range pickBotDifficulties(rng, 2, cfg) {
+			challenge += int32(d)
+		}
+		assert.Equal(t, int32(4), challenge)
+	}
+
+	// Targets past what the range allows stop at the maximum.
+	cfg.MaxDifficulty = matchpb.BotDifficulty_DEFAULT
+	assert.Equal(t,
+		[]matchpb.BotDifficulty{matchpb.BotDifficulty_DEFAULT, matchpb.BotDifficulty_DEFAULT},
+		pickBotDifficulties(rng, 2, cfg))
+}
+
+func TestPickBotFactions(t *testing.T) {
+	prev := &matchpb.Match{Bots: []*matchpb.Bot{{Type: matchpb.FactionType_CORVID}}}
+	cfg := &MatchCfg{BotEnemies: 2, MaxBotTraits: 2}
+	bots, err := pickBotFactions(rng, prev, cfg, maps.Clone(BotCatalog), nil)
+	require.NoError(t, err)
+	assert.Len(t, bots, 2)
+	assert.NotEqual(t, bots[0].GetType(), bots[1].GetType())
+	for _, b := range bots {
+		entry := BotCatalog[b.GetClockwork()]
+		assert.Equal(t, entry.Name, b.GetName())
+		assert.Equal(t, entry.Mirrors, int32(b.GetType()))
+		assert.LessOrEqual(t, len(b.GetTraits()), 2)
+		assert.Subset(t, entry.Traits, b.GetTraits())
+	}
+}
+
+func TestGenerateNewMatchAvoidsCollisions(t *testing.T) {
+	prev, err := parseMatch("match.json")
+	assert.NoError(t, err)
+	cfg := &MatchCfg{UseHirelings: true, Players: 1, BotEnemies: 2}
+	for range 20 {
+		match, err := generateNewMatch(rng, prev, maps.Clone(FactionNames), maps.Clone(BotCatalog), maps.Clone(Hirelings), cfg)
+		require.NoError(t, err)
+		inPlay := map[matchpb.FactionType]bool{match.GetPlayers()[0].GetType(): true}
+		for _, b := range match.GetBots() {
+			assert.False(t, inPlay[b.GetType()], "bot %v mirrors a faction in play", b.GetName())
+			inPlay[b.GetType()] = true
+		}
+		for _, h := range match.GetHirelings() {
+			assert.False(t, inPlay[h.GetType()], "hireling %v is a faction in play", h.GetName())
+		}
+	}
+}
+
+func TestGenerateNewMatchPlayers(t *testing.T) {
+	cfg := defaultMatchCfg()
+	cfg.Players, cfg.BotEnemies = 3, 1
+	for range 20 {
+		match, err := generateNewMatch(rng, &matchpb.Match{}, maps.Clone(FactionNames), maps.Clone(BotCatalog), maps.Clone(Hirelings), &cfg)
+		require.NoError(t, err)
+		assert.Len(t, match.GetPlayers(), 3)
+		assert.Empty(t, Validate(match))
+
+		rerolled, err := rerollComponent(rng, match, matchpb.Component_PLAYERS, &cfg)
+		require.NoError(t, err)
+		assert.Len(t, rerolled.GetPlayers(), 3)
+		assert.Empty(t, Validate(rerolled))
+	}
+
+	cfg.Players = int32(len(FactionNames)) + 1
+	_, err := generateNewMatch(rng, &matchpb.Match{}, maps.Clone(FactionNames), maps.Clone(BotCatalog), maps.Clone(Hirelings), &cfg)
+	assert.Error(t, err)
+}
+
+func TestPickDeck(t *testing.T) {
+	cfg := defaultMatchCfg()
+	prev := &matchpb.Match{Deck: matchpb.Deck_EXILES_AND_PARTISANS}
+	counts := map[matchpb.Deck]int{}
+	for range 1000 {
+		counts[pickDeck(rng, prev, &cfg)]++
+	}
+	assert.Len(t, counts, 2)
+	assert.Greater(t, counts[matchpb.Deck_EXILES_AND_PARTISANS], counts[matchpb.Deck_BASE_DECK])
+
+	cfg.UseExilesDeck = false
+	for range 20 {
+		assert.Equal(t, matchpb.Deck_BASE_DECK, pickDeck(rng, prev, &cfg))
+	}
+	match, err := rerollComponent(rng, &matchpb.Match{}, matchpb.Component_DECK, &cfg)
+	require.NoError(t, err)
+	assert.Equal(t, matchpb.Deck_BASE_DECK, match.GetDeck())
+}
@@ -9,6 +9,7 @@ package matchpb
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
 )
@@ -99,6 +100,75 @@ func (FactionType) EnumDescriptor() ([]byte, []int) {
 	return file_match_proto_rawDescGZIP(), []int{0}
 }
 
+// BotType is the Clockwork bot a bot plays, unspecified for bots of older
+// matches that only recorded the faction they mirror.
+type BotType int32
+
+const (
+	BotType_BOT_TYPE_UNSPECIFIED BotType = 0
+	BotType_MECHANICAL_MARQUISE  BotType = 10
+	BotType_ELECTRIC_EYRIE       BotType = 1
+	BotType_AUTOMATED_ALLIANCE   BotType = 2
+	BotType_VAGABOT              BotType = 3
+	BotType_RIVERFOLK_ROBOTS     BotType = 4
+	BotType_LOGICAL_LIZARDS      BotType = 5
+	BotType_DRILLBIT_DUCHY       BotType = 8
+	BotType_COGWHEEL_CORVIDS     BotType = 9
+)
+
+// Enum value maps for BotType.
+var (
+	BotType_name = map[int32]string{
+		0:  "BOT_TYPE_UNSPECIFIED",
+		10: "MECHANICAL_MARQUISE",
+		1:  "ELECTRIC_EYRIE",
+		2:  "AUTOMATED_ALLIANCE",
+		3:  "VAGABOT",
+		4:  "RIVERFOLK_ROBOTS",
+		5:  "LOGICAL_LIZARDS",
+		8:  "DRILLBIT_DUCHY",
+		9:  "COGWHEEL_CORVIDS",
+	}
+	BotType_value = map[string]int32{
+		"BOT_TYPE_UNSPECIFIED": 0,
+		"MECHANICAL_MARQUISE":  10,
+		"ELECTRIC_EYRIE":       1,
+		"AUTOMATED_ALLIANCE":   2,
+		"VAGABOT":              3,
+		"RIVERFOLK_ROBOTS":     4,
+		"LOGICAL_LIZARDS":      5,
+		"DRILLBIT_DUCHY":       8,
+		"COGWHEEL_CORVIDS":     9,
+	}
+)
+
+func (x BotType) Enum() *BotType {
+	p := new(BotType)
+	*p = x
+	return p
+}
+
+func (x BotType) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BotType) Descriptor() protoreflect.EnumDescriptor {
+	return file_match_proto_enumTypes[1].Descriptor()
+}
+
+func (BotType) Type() protoreflect.EnumType {
+	return &file_match_proto_enumTypes[1]
+}
+
+func (x BotType) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BotType.Descriptor instead.
+func (BotType) EnumDescriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{1}
+}
+
 type MapType int32
 
 const (
@@ -135,11 +205,11 @@ func (x MapType) String() string {
 }
 
 func (MapType) Descriptor() protoreflect.EnumDescriptor {
-	return file_match_proto_enumTypes[1].Descriptor()
+	return file_match_proto_enumTypes[2].Descriptor()
 }
 
 func (MapType) Type() protoreflect.EnumType {
-	return &file_match_proto_enumTypes[1]
+	return &file_match_proto_enumTypes[2]
 }
 
 func (x MapType) Number() protoreflect.EnumNumber {
@@ -148,7 +218,7 @@ func (x MapType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use MapType.Descriptor instead.
 func (MapType) EnumDescriptor() ([]byte, []int) {
-	return file_match_proto_rawDescGZIP(), []int{1}
+	return file_match_proto_rawDescGZIP(), []int{2}
 }
 
 type LandmarkType int32
@@ -193,11 +263,11 @@ func (x LandmarkType) String() string {
 }
 
 func (LandmarkType) Descriptor() protoreflect.EnumDescriptor {
-	return file_match_proto_enumTypes[2].Descriptor()
+	return file_match_proto_enumTypes[3].Descriptor()
 }
 
 func (LandmarkType) Type() protoreflect.EnumType {
-	return &file_match_proto_enumTypes[2]
+	return &file_match_proto_enumTypes[3]
 }
 
 func (x LandmarkType) Number() protoreflect.EnumNumber {
@@ -206,7 +276,365 @@ func (x LandmarkType) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use LandmarkType.Descriptor instead.
 func (LandmarkType) EnumDescriptor() ([]byte, []int) {
-	return file_match_proto_rawDescGZIP(), []int{2}
+	return file_match_proto_rawDescGZIP(), []int{3}
+}
+
+type HirelingStatus int32
+
+const (
+	HirelingStatus_PROMOTED HirelingStatus = 0
+	HirelingStatus_DEMOTED  HirelingStatus = 1
+)
+
+// Enum value maps for HirelingStatus.
+var (
+	HirelingStatus_name = map[int32]string{
+		0: "PROMOTED",
+		1: "DEMOTED",
+	}
+	HirelingStatus_value = map[string]int32{
+		"PROMOTED": 0,
+		"DEMOTED":  1,
+	}
+)
+
+func (x HirelingStatus) Enum() *HirelingStatus {
+	p := new(HirelingStatus)
+	*p = x
+	return p
+}
+
+func (x HirelingStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (HirelingStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_match_proto_enumTypes[4].Descriptor()
+}
+
+func (HirelingStatus) Type() protoreflect.EnumType {
+	return &file_match_proto_enumTypes[4]
+}
+
+func (x HirelingStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use HirelingStatus.Descriptor instead.
+func (HirelingStatus) EnumDescriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{4}
+}
+
+type BotDifficulty int32
+
+const (
+	BotDifficulty_EASY        BotDifficulty = 0
+	BotDifficulty_DEFAULT     BotDifficulty = 1
+	BotDifficulty_CHALLENGING BotDifficulty = 2
+	BotDifficulty_NIGHTMARE   BotDifficulty = 3
+)
+
+// Enum value maps for BotDifficulty.
+var (
+	BotDifficulty_name = map[int32]string{
+		0: "EASY",
+		1: "DEFAULT",
+		2: "CHALLENGING",
+		3: "NIGHTMARE",
+	}
+	BotDifficulty_value = map[string]int32{
+		"EASY":        0,
+		"DEFAULT":     1,
+		"CHALLENGING": 2,
+		"NIGHTMARE":   3,
+	}
+)
+
+func (x BotDifficulty) Enum() *BotDifficulty {
+	p := new(BotDifficulty)
+	*p = x
+	return p
+}
+
+func (x BotDifficulty) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (BotDifficulty) Descriptor() protoreflect.EnumDescriptor {
+	return file_match_proto_enumTypes[5].Descriptor()
+}
+
+func (BotDifficulty) Type() protoreflect.EnumType {
+	return &file_match_proto_enumTypes[5]
+}
+
+func (x BotDifficulty) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use BotDifficulty.Descriptor instead.
+func (BotDifficulty) EnumDescriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{5}
+}
+
+type PairingMode int32
+
+const (
+	PairingMode_ALLOW_PAIRINGS       PairingMode = 0
+	PairingMode_DOWN_WEIGHT_PAIRINGS PairingMode = 1
+	PairingMode_FORBID_PAIRINGS      PairingMode = 2
+)
+
+// Enum value maps for PairingMode.
+var (
+	PairingMode_name = map[int32]string{
+		0: "ALLOW_PAIRINGS",
+		1: "DOWN_WEIGHT_PAIRINGS",
+		2: "FORBID_PAIRINGS",
+	}
+	PairingMode_value = map[string]int32{
+		"ALLOW_PAIRINGS":       0,
+		"DOWN_WEIGHT_PAIRINGS": 1,
+		"FORBID_PAIRINGS":      2,
+	}
+)
+
+func (x PairingMode) Enum() *PairingMode {
+	p := new(PairingMode)
+	*p = x
+	return p
+}
+
+func (x PairingMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (PairingMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_match_proto_enumTypes[6].Descriptor()
+}
+
+func (PairingMode) Type() protoreflect.EnumType {
+	return &file_match_proto_enumTypes[6]
+}
+
+func (x PairingMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use PairingMode.Descriptor instead.
+func (PairingMode) EnumDescriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{6}
+}
+
+type Deck int32
+
+const (
+	// Older matches didn't record the deck.
+	Deck_UNKNOWN_DECK         Deck = 0
+	Deck_BASE_DECK            Deck = 1
+	Deck_EXILES_AND_PARTISANS Deck = 2
+)
+
+// Enum value maps for Deck.
+var (
+	Deck_name = map[int32]string{
+		0: "UNKNOWN_DECK",
+		1: "BASE_DECK",
+		2: "EXILES_AND_PARTISANS",
+	}
+	Deck_value = map[string]int32{
+		"UNKNOWN_DECK":         0,
+		"BASE_DECK":            1,
+		"EXILES_AND_PARTISANS": 2,
+	}
+)
+
+func (x Deck) Enum() *Deck {
+	p := new(Deck)
+	*p = x
+	return p
+}
+
+func (x Deck) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Deck) Descriptor() protoreflect.EnumDescriptor {
+	return file_match_proto_enumTypes[7].Descriptor()
+}
+
+func (Deck) Type() protoreflect.EnumType {
+	return &file_match_proto_enumTypes[7]
+}
+
+func (x Deck) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Deck.Descriptor instead.
+func (Deck) EnumDescriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{7}
+}
+
+type SetupOrder int32
+
+const (
+	// TURN_ORDER sets up from the first player on, clockwise.
+	SetupOrder_TURN_ORDER SetupOrder = 0
+	// FACTION_PRIORITY sets up in the order of the factions' setup letters.
+	SetupOrder_FACTION_PRIORITY SetupOrder = 1
+)
+
+// Enum value maps for SetupOrder.
+var (
+	SetupOrder_name = map[int32]string{
+		0: "TURN_ORDER",
+		1: "FACTION_PRIORITY",
+	}
+	SetupOrder_value = map[string]int32{
+		"TURN_ORDER":       0,
+		"FACTION_PRIORITY": 1,
+	}
+)
+
+func (x SetupOrder) Enum() *SetupOrder {
+	p := new(SetupOrder)
+	*p = x
+	return p
+}
+
+func (x SetupOrder) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SetupOrder) Descriptor() protoreflect.EnumDescriptor {
+	return file_match_proto_enumTypes[8].Descriptor()
+}
+
+func (SetupOrder) Type() protoreflect.EnumType {
+	return &file_match_proto_enumTypes[8]
+}
+
+func (x SetupOrder) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SetupOrder.Descriptor instead.
+func (SetupOrder) EnumDescriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{8}
+}
+
+type SetupMode int32
+
+const (
+	SetupMode_STANDARD_SETUP SetupMode = 0
+	// ADSET follows the advanced setup rules used in tournaments.
+	SetupMode_ADSET SetupMode = 1
+)
+
+// Enum value maps for SetupMode.
+var (
+	SetupMode_name = map[int32]string{
+		0: "STANDARD_SETUP",
+		1: "ADSET",
+	}
+	SetupMode_value = map[string]int32{
+		"STANDARD_SETUP": 0,
+		"ADSET":          1,
+	}
+)
+
+func (x SetupMode) Enum() *SetupMode {
+	p := new(SetupMode)
+	*p = x
+	return p
+}
+
+func (x SetupMode) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (SetupMode) Descriptor() protoreflect.EnumDescriptor {
+	return file_match_proto_enumTypes[9].Descriptor()
+}
+
+func (SetupMode) Type() protoreflect.EnumType {
+	return &file_match_proto_enumTypes[9]
+}
+
+func (x SetupMode) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use SetupMode.Descriptor instead.
+func (SetupMode) EnumDescriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{9}
+}
+
+type Component int32
+
+const (
+	// Requests that leave the component out are turned down rather than
+	// rerolling the players.
+	Component_COMPONENT_UNSPECIFIED Component = 0
+	Component_PLAYERS               Component = 7
+	Component_BOTS                  Component = 1
+	Component_HIRELINGS             Component = 2
+	Component_MAP                   Component = 3
+	Component_LANDMARKS             Component = 4
+	Component_DECK                  Component = 5
+	Component_SEATS                 Component = 6
+)
+
+// Enum value maps for Component.
+var (
+	Component_name = map[int32]string{
+		0: "COMPONENT_UNSPECIFIED",
+		7: "PLAYERS",
+		1: "BOTS",
+		2: "HIRELINGS",
+		3: "MAP",
+		4: "LANDMARKS",
+		5: "DECK",
+		6: "SEATS",
+	}
+	Component_value = map[string]int32{
+		"COMPONENT_UNSPECIFIED": 0,
+		"PLAYERS":               7,
+		"BOTS":                  1,
+		"HIRELINGS":             2,
+		"MAP":                   3,
+		"LANDMARKS":             4,
+		"DECK":                  5,
+		"SEATS":                 6,
+	}
+)
+
+func (x Component) Enum() *Component {
+	p := new(Component)
+	*p = x
+	return p
+}
+
+func (x Component) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (Component) Descriptor() protoreflect.EnumDescriptor {
+	return file_match_proto_enumTypes[10].Descriptor()
+}
+
+func (Component) Type() protoreflect.EnumType {
+	return &file_match_proto_enumTypes[10]
+}
+
+func (x Component) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use Component.Descriptor instead.
+func (Component) EnumDescriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{10}
 }
 
 type Suit int32
@@ -245,11 +673,11 @@ func (x Suit) String() string {
 }
 
 func (Suit) Descriptor() protoreflect.EnumDescriptor {
-	return file_match_proto_enumTypes[3].Descriptor()
+	return file_match_proto_enumTypes[11].Descriptor()
 }
 
 func (Suit) Type() protoreflect.EnumType {
-	return &file_match_proto_enumTypes[3]
+	return &file_match_proto_enumTypes[11]
 }
 
 func (x Suit) Number() protoreflect.EnumNumber {
@@ -258,7 +686,7 @@ func (x Suit) Number() protoreflect.EnumNumber {
 
 // Deprecated: Use Suit.Descriptor instead.
 func (Suit) EnumDescriptor() ([]byte, []int) {
-	return file_match_proto_rawDescGZIP(), []int{3}
+	return file_match_proto_rawDescGZIP(), []int{11}
 }
 
 type Match struct {
@@ -267,10 +695,22 @@ type Match struct {
 	unknownFields protoimpl.UnknownFields
 
 	Players   []*Faction  `protobuf:"bytes,1,rep,name=Players,proto3" json:"Players,omitempty"`
-	Bots      []*Faction  `protobuf:"bytes,2,rep,name=Bots,proto3" json:"Bots,omitempty"`
-	Hirelings []*Faction  `protobuf:"bytes,3,rep,name=Hirelings,proto3" json:"Hirelings,omitempty"`
+	Bots      []*Bot      `protobuf:"bytes,2,rep,name=Bots,proto3" json:"Bots,omitempty"`
+	Hirelings []*Hireling `protobuf:"bytes,3,rep,name=Hirelings,proto3" json:"Hirelings,omitempty"`
 	Map       *MapVal     `protobuf:"bytes,4,opt,name=Map,proto3" json:"Map,omitempty"`
 	Landmarks []*Landmark `protobuf:"bytes,5,rep,name=Landmarks,proto3" json:"Landmarks,omitempty"`
+	// Clearings holds the suit of every clearing when the setup decides
+	// them, the map's printed suits apply otherwise.
+	Clearings []*Clearing `protobuf:"bytes,6,rep,name=Clearings,proto3" json:"Clearings,omitempty"`
+	Deck      Deck        `protobuf:"varint,7,opt,name=Deck,proto3,enum=match.Deck" json:"Deck,omitempty"`
+	// Seats go clockwise around the table, holding every player and bot.
+	Seats []*Seat `protobuf:"bytes,8,rep,name=Seats,proto3" json:"Seats,omitempty"`
+	// FirstSeat is the number of the seat taking the first turn, seats being
+	// numbered from 1.
+	FirstSeat int32 `protobuf:"varint,9,opt,name=FirstSeat,proto3" json:"FirstSeat,omitempty"`
+	// Homes are the starting clearings of the factions that have one, in
+	// the order they were set up.
+	Homes []*Home `protobuf:"bytes,10,rep,name=Homes,proto3" json:"Homes,omitempty"`
 }
 
 func (x *Match) Reset() {
@@ -310,14 +750,14 @@ func (x *Match) GetPlayers() []*Faction {
 	return nil
 }
 
-func (x *Match) GetBots() []*Faction {
+func (x *Match) GetBots() []*Bot {
 	if x != nil {
 		return x.Bots
 	}
 	return nil
 }
 
-func (x *Match) GetHirelings() []*Faction {
+func (x *Match) GetHirelings() []*Hireling {
 	if x != nil {
 		return x.Hirelings
 	}
@@ -338,29 +778,66 @@ func (x *Match) GetLandmarks() []*Landmark {
 	return nil
 }
 
-type MapVal struct {
+func (x *Match) GetClearings() []*Clearing {
+	if x != nil {
+		return x.Clearings
+	}
+	return nil
+}
+
+func (x *Match) GetDeck() Deck {
+	if x != nil {
+		return x.Deck
+	}
+	return Deck_UNKNOWN_DECK
+}
+
+func (x *Match) GetSeats() []*Seat {
+	if x != nil {
+		return x.Seats
+	}
+	return nil
+}
+
+func (x *Match) GetFirstSeat() int32 {
+	if x != nil {
+		return x.FirstSeat
+	}
+	return 0
+}
+
+func (x *Match) GetHomes() []*Home {
+	if x != nil {
+		return x.Homes
+	}
+	return nil
+}
+
+type MatchRecord struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Type MapType `protobuf:"varint,1,opt,name=Type,proto3,enum=match.MapType" json:"Type,omitempty"`
-	Name string  `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	Id        string                 `protobuf:"bytes,1,opt,name=Id,proto3" json:"Id,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	Match     *Match                 `protobuf:"bytes,3,opt,name=Match,proto3" json:"Match,omitempty"`
+	Results   []*Result              `protobuf:"bytes,4,rep,name=Results,proto3" json:"Results,omitempty"`
 }
 
-func (x *MapVal) Reset() {
-	*x = MapVal{}
+func (x *MatchRecord) Reset() {
+	*x = MatchRecord{}
 	mi := &file_match_proto_msgTypes[1]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *MapVal) String() string {
+func (x *MatchRecord) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*MapVal) ProtoMessage() {}
+func (*MatchRecord) ProtoMessage() {}
 
-func (x *MapVal) ProtoReflect() protoreflect.Message {
+func (x *MatchRecord) ProtoReflect() protoreflect.Message {
 	mi := &file_match_proto_msgTypes[1]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -372,48 +849,69 @@ func (x *MapVal) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use MapVal.ProtoReflect.Descriptor instead.
-func (*MapVal) Descriptor() ([]byte, []int) {
+// Deprecated: Use MatchRecord.ProtoReflect.Descriptor instead.
+func (*MatchRecord) Descriptor() ([]byte, []int) {
 	return file_match_proto_rawDescGZIP(), []int{1}
 }
 
-func (x *MapVal) GetType() MapType {
+func (x *MatchRecord) GetId() string {
 	if x != nil {
-		return x.Type
+		return x.Id
 	}
-	return MapType_AUTUMN
+	return ""
 }
 
-func (x *MapVal) GetName() string {
+func (x *MatchRecord) GetCreatedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Name
+		return x.CreatedAt
 	}
-	return ""
+	return nil
 }
 
-type Landmark struct {
+func (x *MatchRecord) GetMatch() *Match {
+	if x != nil {
+		return x.Match
+	}
+	return nil
+}
+
+func (x *MatchRecord) GetResults() []*Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+// Envelope is how a match is stored in the history, along with what it was
+// generated from.
+type Envelope struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Type LandmarkType `protobuf:"varint,1,opt,name=Type,proto3,enum=match.LandmarkType" json:"Type,omitempty"`
-	Name string       `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	SchemaVersion    int32  `protobuf:"varint,1,opt,name=SchemaVersion,proto3" json:"SchemaVersion,omitempty"`
+	GeneratorVersion string `protobuf:"bytes,2,opt,name=GeneratorVersion,proto3" json:"GeneratorVersion,omitempty"`
+	// Seed generates the match from Config, 0 once a component was rerolled.
+	Seed int64 `protobuf:"varint,3,opt,name=Seed,proto3" json:"Seed,omitempty"`
+	// Config is what the match was generated or last rerolled with.
+	Config *MatchConfig `protobuf:"bytes,4,opt,name=Config,proto3" json:"Config,omitempty"`
+	Record *MatchRecord `protobuf:"bytes,5,opt,name=Record,proto3" json:"Record,omitempty"`
 }
 
-func (x *Landmark) Reset() {
-	*x = Landmark{}
+func (x *Envelope) Reset() {
+	*x = Envelope{}
 	mi := &file_match_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Landmark) String() string {
+func (x *Envelope) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Landmark) ProtoMessage() {}
+func (*Envelope) ProtoMessage() {}
 
-func (x *Landmark) ProtoReflect() protoreflect.Message {
+func (x *Envelope) ProtoReflect() protoreflect.Message {
 	mi := &file_match_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -425,48 +923,71 @@ func (x *Landmark) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Landmark.ProtoReflect.Descriptor instead.
-func (*Landmark) Descriptor() ([]byte, []int) {
+// Deprecated: Use Envelope.ProtoReflect.Descriptor instead.
+func (*Envelope) Descriptor() ([]byte, []int) {
 	return file_match_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *Landmark) GetType() LandmarkType {
+func (x *Envelope) GetSchemaVersion() int32 {
 	if x != nil {
-		return x.Type
+		return x.SchemaVersion
 	}
-	return LandmarkType_TOWER
+	return 0
 }
 
-func (x *Landmark) GetName() string {
+func (x *Envelope) GetGeneratorVersion() string {
 	if x != nil {
-		return x.Name
+		return x.GeneratorVersion
 	}
 	return ""
 }
 
-type Faction struct {
+func (x *Envelope) GetSeed() int64 {
+	if x != nil {
+		return x.Seed
+	}
+	return 0
+}
+
+func (x *Envelope) GetConfig() *MatchConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *Envelope) GetRecord() *MatchRecord {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+type Result struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Type FactionType `protobuf:"varint,1,opt,name=Type,proto3,enum=match.FactionType" json:"Type,omitempty"`
-	Name string      `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	Player  string      `protobuf:"bytes,1,opt,name=Player,proto3" json:"Player,omitempty"`
+	Faction FactionType `protobuf:"varint,2,opt,name=Faction,proto3,enum=match.FactionType" json:"Faction,omitempty"`
+	Score   int32       `protobuf:"varint,3,opt,name=Score,proto3" json:"Score,omitempty"`
+	Winner  bool        `protobuf:"varint,4,opt,name=Winner,proto3" json:"Winner,omitempty"`
 }
 
-func (x *Faction) Reset() {
-	*x = Faction{}
+func (x *Result) Reset() {
+	*x = Result{}
 	mi := &file_match_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Faction) String() string {
+func (x *Result) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Faction) ProtoMessage() {}
+func (*Result) ProtoMessage() {}
 
-func (x *Faction) ProtoReflect() protoreflect.Message {
+func (x *Result) ProtoReflect() protoreflect.Message {
 	mi := &file_match_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -478,48 +999,87 @@ func (x *Faction) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Faction.ProtoReflect.Descriptor instead.
-func (*Faction) Descriptor() ([]byte, []int) {
+// Deprecated: Use Result.ProtoReflect.Descriptor instead.
+func (*Result) Descriptor() ([]byte, []int) {
 	return file_match_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *Faction) GetType() FactionType {
+func (x *Result) GetPlayer() string {
 	if x != nil {
-		return x.Type
+		return x.Player
+	}
+	return ""
+}
+
+func (x *Result) GetFaction() FactionType {
+	if x != nil {
+		return x.Faction
 	}
 	return FactionType_MARQUISE
 }
 
-func (x *Faction) GetName() string {
+func (x *Result) GetScore() int32 {
 	if x != nil {
-		return x.Name
+		return x.Score
 	}
-	return ""
+	return 0
 }
 
-type Clearing struct {
+func (x *Result) GetWinner() bool {
+	if x != nil {
+		return x.Winner
+	}
+	return false
+}
+
+type MatchConfig struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Suit   Suit  `protobuf:"varint,1,opt,name=Suit,proto3,enum=match.Suit" json:"Suit,omitempty"`
-	Number int32 `protobuf:"varint,2,opt,name=Number,proto3" json:"Number,omitempty"`
-}
-
-func (x *Clearing) Reset() {
-	*x = Clearing{}
+	UseHirelings    bool          `protobuf:"varint,1,opt,name=UseHirelings,proto3" json:"UseHirelings,omitempty"`
+	UseLandmarks    bool          `protobuf:"varint,2,opt,name=UseLandmarks,proto3" json:"UseLandmarks,omitempty"`
+	BotEnemies      int32         `protobuf:"varint,3,opt,name=BotEnemies,proto3" json:"BotEnemies,omitempty"`
+	Players         int32         `protobuf:"varint,4,opt,name=Players,proto3" json:"Players,omitempty"`
+	MinDifficulty   BotDifficulty `protobuf:"varint,5,opt,name=MinDifficulty,proto3,enum=match.BotDifficulty" json:"MinDifficulty,omitempty"`
+	MaxDifficulty   BotDifficulty `protobuf:"varint,6,opt,name=MaxDifficulty,proto3,enum=match.BotDifficulty" json:"MaxDifficulty,omitempty"`
+	TargetChallenge int32         `protobuf:"varint,7,opt,name=TargetChallenge,proto3" json:"TargetChallenge,omitempty"`
+	MaxBotTraits    int32         `protobuf:"varint,8,opt,name=MaxBotTraits,proto3" json:"MaxBotTraits,omitempty"`
+	MinScore        float64       `protobuf:"fixed64,9,opt,name=MinScore,proto3" json:"MinScore,omitempty"`
+	MaxScore        float64       `protobuf:"fixed64,10,opt,name=MaxScore,proto3" json:"MaxScore,omitempty"`
+	Pairings        PairingMode   `protobuf:"varint,11,opt,name=Pairings,proto3,enum=match.PairingMode" json:"Pairings,omitempty"`
+	BadPairing      float64       `protobuf:"fixed64,12,opt,name=BadPairing,proto3" json:"BadPairing,omitempty"`
+	Setup           SetupMode     `protobuf:"varint,13,opt,name=Setup,proto3,enum=match.SetupMode" json:"Setup,omitempty"`
+	// UseExilesDeck puts the Exiles & Partisans deck in the running next to
+	// the base deck.
+	UseExilesDeck bool       `protobuf:"varint,14,opt,name=UseExilesDeck,proto3" json:"UseExilesDeck,omitempty"`
+	SetupOrder    SetupOrder `protobuf:"varint,15,opt,name=SetupOrder,proto3,enum=match.SetupOrder" json:"SetupOrder,omitempty"`
+	// Coverage prefers what CoveragePlayer, or the whole group when empty,
+	// hasn't played yet.
+	Coverage       bool   `protobuf:"varint,16,opt,name=Coverage,proto3" json:"Coverage,omitempty"`
+	CoveragePlayer string `protobuf:"bytes,17,opt,name=CoveragePlayer,proto3" json:"CoveragePlayer,omitempty"`
+	// Factions are the players' factions when they were handed out instead
+	// of rolled, as campaigns do.
+	Factions []FactionType `protobuf:"varint,18,rep,packed,name=Factions,proto3,enum=match.FactionType" json:"Factions,omitempty"`
+	// Covered is what the coverage goal had seen when the match was
+	// generated, so the seed and config generate it again.
+	Covered *CoveredItems `protobuf:"bytes,19,opt,name=Covered,proto3" json:"Covered,omitempty"`
+}
+
+func (x *MatchConfig) Reset() {
+	*x = MatchConfig{}
 	mi := &file_match_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *Clearing) String() string {
+func (x *MatchConfig) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*Clearing) ProtoMessage() {}
+func (*MatchConfig) ProtoMessage() {}
 
-func (x *Clearing) ProtoReflect() protoreflect.Message {
+func (x *MatchConfig) ProtoReflect() protoreflect.Message {
 	mi := &file_match_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -531,88 +1091,1513 @@ func (x *Clearing) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use Clearing.ProtoReflect.Descriptor instead.
-func (*Clearing) Descriptor() ([]byte, []int) {
+// Deprecated: Use MatchConfig.ProtoReflect.Descriptor instead.
+func (*MatchConfig) Descriptor() ([]byte, []int) {
 	return file_match_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *Clearing) GetSuit() Suit {
+func (x *MatchConfig) GetUseHirelings() bool {
 	if x != nil {
-		return x.Suit
+		return x.UseHirelings
 	}
-	return Suit_BIRD
+	return false
 }
 
-func (x *Clearing) GetNumber() int32 {
+func (x *MatchConfig) GetUseLandmarks() bool {
 	if x != nil {
-		return x.Number
+		return x.UseLandmarks
+	}
+	return false
+}
+
+func (x *MatchConfig) GetBotEnemies() int32 {
+	if x != nil {
+		return x.BotEnemies
+	}
+	return 0
+}
+
+func (x *MatchConfig) GetPlayers() int32 {
+	if x != nil {
+		return x.Players
+	}
+	return 0
+}
+
+func (x *MatchConfig) GetMinDifficulty() BotDifficulty {
+	if x != nil {
+		return x.MinDifficulty
+	}
+	return BotDifficulty_EASY
+}
+
+func (x *MatchConfig) GetMaxDifficulty() BotDifficulty {
+	if x != nil {
+		return x.MaxDifficulty
+	}
+	return BotDifficulty_EASY
+}
+
+func (x *MatchConfig) GetTargetChallenge() int32 {
+	if x != nil {
+		return x.TargetChallenge
+	}
+	return 0
+}
+
+func (x *MatchConfig) GetMaxBotTraits() int32 {
+	if x != nil {
+		return x.MaxBotTraits
+	}
+	return 0
+}
+
+func (x *MatchConfig) GetMinScore() float64 {
+	if x != nil {
+		return x.MinScore
+	}
+	return 0
+}
+
+func (x *MatchConfig) GetMaxScore() float64 {
+	if x != nil {
+		return x.MaxScore
+	}
+	return 0
+}
+
+func (x *MatchConfig) GetPairings() PairingMode {
+	if x != nil {
+		return x.Pairings
+	}
+	return PairingMode_ALLOW_PAIRINGS
+}
+
+func (x *MatchConfig) GetBadPairing() float64 {
+	if x != nil {
+		return x.BadPairing
 	}
 	return 0
 }
 
+func (x *MatchConfig) GetSetup() SetupMode {
+	if x != nil {
+		return x.Setup
+	}
+	return SetupMode_STANDARD_SETUP
+}
+
+func (x *MatchConfig) GetUseExilesDeck() bool {
+	if x != nil {
+		return x.UseExilesDeck
+	}
+	return false
+}
+
+func (x *MatchConfig) GetSetupOrder() SetupOrder {
+	if x != nil {
+		return x.SetupOrder
+	}
+	return SetupOrder_TURN_ORDER
+}
+
+func (x *MatchConfig) GetCoverage() bool {
+	if x != nil {
+		return x.Coverage
+	}
+	return false
+}
+
+func (x *MatchConfig) GetCoveragePlayer() string {
+	if x != nil {
+		return x.CoveragePlayer
+	}
+	return ""
+}
+
+func (x *MatchConfig) GetFactions() []FactionType {
+	if x != nil {
+		return x.Factions
+	}
+	return nil
+}
+
+func (x *MatchConfig) GetCovered() *CoveredItems {
+	if x != nil {
+		return x.Covered
+	}
+	return nil
+}
+
+// CoveredItems lists covered items by the values the generator keys its
+// pools by.
+type CoveredItems struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Factions  []int32 `protobuf:"varint,1,rep,packed,name=Factions,proto3" json:"Factions,omitempty"`
+	Bots      []int32 `protobuf:"varint,2,rep,packed,name=Bots,proto3" json:"Bots,omitempty"`
+	Hirelings []int32 `protobuf:"varint,3,rep,packed,name=Hirelings,proto3" json:"Hirelings,omitempty"`
+	Maps      []int32 `protobuf:"varint,4,rep,packed,name=Maps,proto3" json:"Maps,omitempty"`
+	Landmarks []int32 `protobuf:"varint,5,rep,packed,name=Landmarks,proto3" json:"Landmarks,omitempty"`
+}
+
+func (x *CoveredItems) Reset() {
+	*x = CoveredItems{}
+	mi := &file_match_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CoveredItems) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CoveredItems) ProtoMessage() {}
+
+func (x *CoveredItems) ProtoReflect() protoreflect.Message {
+	mi := &file_match_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CoveredItems.ProtoReflect.Descriptor instead.
+func (*CoveredItems) Descriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CoveredItems) GetFactions() []int32 {
+	if x != nil {
+		return x.Factions
+	}
+	return nil
+}
+
+func (x *CoveredItems) GetBots() []int32 {
+	if x != nil {
+		return x.Bots
+	}
+	return nil
+}
+
+func (x *CoveredItems) GetHirelings() []int32 {
+	if x != nil {
+		return x.Hirelings
+	}
+	return nil
+}
+
+func (x *CoveredItems) GetMaps() []int32 {
+	if x != nil {
+		return x.Maps
+	}
+	return nil
+}
+
+func (x *CoveredItems) GetLandmarks() []int32 {
+	if x != nil {
+		return x.Landmarks
+	}
+	return nil
+}
+
+type MapVal struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type MapType `protobuf:"varint,1,opt,name=Type,proto3,enum=match.MapType" json:"Type,omitempty"`
+	Name string  `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+}
+
+func (x *MapVal) Reset() {
+	*x = MapVal{}
+	mi := &file_match_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MapVal) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MapVal) ProtoMessage() {}
+
+func (x *MapVal) ProtoReflect() protoreflect.Message {
+	mi := &file_match_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MapVal.ProtoReflect.Descriptor instead.
+func (*MapVal) Descriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *MapVal) GetType() MapType {
+	if x != nil {
+		return x.Type
+	}
+	return MapType_AUTUMN
+}
+
+func (x *MapVal) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type Landmark struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type LandmarkType `protobuf:"varint,1,opt,name=Type,proto3,enum=match.LandmarkType" json:"Type,omitempty"`
+	Name string       `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+}
+
+func (x *Landmark) Reset() {
+	*x = Landmark{}
+	mi := &file_match_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Landmark) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Landmark) ProtoMessage() {}
+
+func (x *Landmark) ProtoReflect() protoreflect.Message {
+	mi := &file_match_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Landmark.ProtoReflect.Descriptor instead.
+func (*Landmark) Descriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *Landmark) GetType() LandmarkType {
+	if x != nil {
+		return x.Type
+	}
+	return LandmarkType_TOWER
+}
+
+func (x *Landmark) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type Faction struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type FactionType `protobuf:"varint,1,opt,name=Type,proto3,enum=match.FactionType" json:"Type,omitempty"`
+	Name string      `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+}
+
+func (x *Faction) Reset() {
+	*x = Faction{}
+	mi := &file_match_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Faction) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Faction) ProtoMessage() {}
+
+func (x *Faction) ProtoReflect() protoreflect.Message {
+	mi := &file_match_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Faction.ProtoReflect.Descriptor instead.
+func (*Faction) Descriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *Faction) GetType() FactionType {
+	if x != nil {
+		return x.Type
+	}
+	return FactionType_MARQUISE
+}
+
+func (x *Faction) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Bot is wire compatible with Faction so older matches still decode. Type is
+// the faction the bot mirrors.
+type Bot struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type       FactionType   `protobuf:"varint,1,opt,name=Type,proto3,enum=match.FactionType" json:"Type,omitempty"`
+	Name       string        `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	Difficulty BotDifficulty `protobuf:"varint,3,opt,name=Difficulty,proto3,enum=match.BotDifficulty" json:"Difficulty,omitempty"`
+	Traits     []string      `protobuf:"bytes,4,rep,name=Traits,proto3" json:"Traits,omitempty"`
+	Clockwork  BotType       `protobuf:"varint,5,opt,name=Clockwork,proto3,enum=match.BotType" json:"Clockwork,omitempty"`
+}
+
+func (x *Bot) Reset() {
+	*x = Bot{}
+	mi := &file_match_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Bot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Bot) ProtoMessage() {}
+
+func (x *Bot) ProtoReflect() protoreflect.Message {
+	mi := &file_match_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Bot.ProtoReflect.Descriptor instead.
+func (*Bot) Descriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *Bot) GetType() FactionType {
+	if x != nil {
+		return x.Type
+	}
+	return FactionType_MARQUISE
+}
+
+func (x *Bot) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Bot) GetDifficulty() BotDifficulty {
+	if x != nil {
+		return x.Difficulty
+	}
+	return BotDifficulty_EASY
+}
+
+func (x *Bot) GetTraits() []string {
+	if x != nil {
+		return x.Traits
+	}
+	return nil
+}
+
+func (x *Bot) GetClockwork() BotType {
+	if x != nil {
+		return x.Clockwork
+	}
+	return BotType_BOT_TYPE_UNSPECIFIED
+}
+
+// Hireling is wire compatible with Faction so older matches still decode.
+type Hireling struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Type      FactionType    `protobuf:"varint,1,opt,name=Type,proto3,enum=match.FactionType" json:"Type,omitempty"`
+	Name      string         `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	Threshold int32          `protobuf:"varint,3,opt,name=Threshold,proto3" json:"Threshold,omitempty"`
+	Status    HirelingStatus `protobuf:"varint,4,opt,name=Status,proto3,enum=match.HirelingStatus" json:"Status,omitempty"`
+}
+
+func (x *Hireling) Reset() {
+	*x = Hireling{}
+	mi := &file_match_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Hireling) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Hireling) ProtoMessage() {}
+
+func (x *Hireling) ProtoReflect() protoreflect.Message {
+	mi := &file_match_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Hireling.ProtoReflect.Descriptor instead.
+func (*Hireling) Descriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *Hireling) GetType() FactionType {
+	if x != nil {
+		return x.Type
+	}
+	return FactionType_MARQUISE
+}
+
+func (x *Hireling) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Hireling) GetThreshold() int32 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *Hireling) GetStatus() HirelingStatus {
+	if x != nil {
+		return x.Status
+	}
+	return HirelingStatus_PROMOTED
+}
+
+// Seat is a player or bot at the table, told apart by Bot since every faction
+// is only at the table once.
+type Seat struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Faction FactionType `protobuf:"varint,1,opt,name=Faction,proto3,enum=match.FactionType" json:"Faction,omitempty"`
+	Bot     bool        `protobuf:"varint,2,opt,name=Bot,proto3" json:"Bot,omitempty"`
+	// SetupOrder is when the seat sets up, 1 going first.
+	SetupOrder int32 `protobuf:"varint,3,opt,name=SetupOrder,proto3" json:"SetupOrder,omitempty"`
+}
+
+func (x *Seat) Reset() {
+	*x = Seat{}
+	mi := &file_match_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Seat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Seat) ProtoMessage() {}
+
+func (x *Seat) ProtoReflect() protoreflect.Message {
+	mi := &file_match_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Seat.ProtoReflect.Descriptor instead.
+func (*Seat) Descriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *Seat) GetFaction() FactionType {
+	if x != nil {
+		return x.Faction
+	}
+	return FactionType_MARQUISE
+}
+
+func (x *Seat) GetBot() bool {
+	if x != nil {
+		return x.Bot
+	}
+	return false
+}
+
+func (x *Seat) GetSetupOrder() int32 {
+	if x != nil {
+		return x.SetupOrder
+	}
+	return 0
+}
+
+// Home is the clearing a faction starts in, numbered like the map's
+// clearings.
+type Home struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Faction  FactionType `protobuf:"varint,1,opt,name=Faction,proto3,enum=match.FactionType" json:"Faction,omitempty"`
+	Clearing int32       `protobuf:"varint,2,opt,name=Clearing,proto3" json:"Clearing,omitempty"`
+}
+
+func (x *Home) Reset() {
+	*x = Home{}
+	mi := &file_match_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Home) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Home) ProtoMessage() {}
+
+func (x *Home) ProtoReflect() protoreflect.Message {
+	mi := &file_match_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Home.ProtoReflect.Descriptor instead.
+func (*Home) Descriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *Home) GetFaction() FactionType {
+	if x != nil {
+		return x.Faction
+	}
+	return FactionType_MARQUISE
+}
+
+func (x *Home) GetClearing() int32 {
+	if x != nil {
+		return x.Clearing
+	}
+	return 0
+}
+
+type Clearing struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Suit   Suit  `protobuf:"varint,1,opt,name=Suit,proto3,enum=match.Suit" json:"Suit,omitempty"`
+	Number int32 `protobuf:"varint,2,opt,name=Number,proto3" json:"Number,omitempty"`
+}
+
+func (x *Clearing) Reset() {
+	*x = Clearing{}
+	mi := &file_match_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Clearing) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Clearing) ProtoMessage() {}
+
+func (x *Clearing) ProtoReflect() protoreflect.Message {
+	mi := &file_match_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Clearing.ProtoReflect.Descriptor instead.
+func (*Clearing) Descriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Clearing) GetSuit() Suit {
+	if x != nil {
+		return x.Suit
+	}
+	return Suit_BIRD
+}
+
+func (x *Clearing) GetNumber() int32 {
+	if x != nil {
+		return x.Number
+	}
+	return 0
+}
+
+// Campaign is a league the same roster plays over seasons of scheduled
+// games.
+type Campaign struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        string                 `protobuf:"bytes,1,opt,name=Id,proto3" json:"Id,omitempty"`
+	Name      string                 `protobuf:"bytes,2,opt,name=Name,proto3" json:"Name,omitempty"`
+	CreatedAt *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=CreatedAt,proto3" json:"CreatedAt,omitempty"`
+	Roster    []string               `protobuf:"bytes,4,rep,name=Roster,proto3" json:"Roster,omitempty"`
+	// Scoring holds the points for each finishing rank, first place first.
+	// Ranks past its end score nothing.
+	Scoring []int32 `protobuf:"varint,5,rep,packed,name=Scoring,proto3" json:"Scoring,omitempty"`
+	// TableSize is how many of the roster sit at each game.
+	TableSize       int32 `protobuf:"varint,6,opt,name=TableSize,proto3" json:"TableSize,omitempty"`
+	RoundsPerSeason int32 `protobuf:"varint,7,opt,name=RoundsPerSeason,proto3" json:"RoundsPerSeason,omitempty"`
+	// EveryFaction has every player go through all the factions before
+	// playing one again within a season.
+	EveryFaction bool `protobuf:"varint,8,opt,name=EveryFaction,proto3" json:"EveryFaction,omitempty"`
+	// Config generates the games' matches, the table size standing in for
+	// its Players.
+	Config *MatchConfig    `protobuf:"bytes,9,opt,name=Config,proto3" json:"Config,omitempty"`
+	Games  []*CampaignGame `protobuf:"bytes,10,rep,name=Games,proto3" json:"Games,omitempty"`
+}
+
+func (x *Campaign) Reset() {
+	*x = Campaign{}
+	mi := &file_match_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Campaign) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Campaign) ProtoMessage() {}
+
+func (x *Campaign) ProtoReflect() protoreflect.Message {
+	mi := &file_match_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Campaign.ProtoReflect.Descriptor instead.
+func (*Campaign) Descriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Campaign) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Campaign) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *Campaign) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Campaign) GetRoster() []string {
+	if x != nil {
+		return x.Roster
+	}
+	return nil
+}
+
+func (x *Campaign) GetScoring() []int32 {
+	if x != nil {
+		return x.Scoring
+	}
+	return nil
+}
+
+func (x *Campaign) GetTableSize() int32 {
+	if x != nil {
+		return x.TableSize
+	}
+	return 0
+}
+
+func (x *Campaign) GetRoundsPerSeason() int32 {
+	if x != nil {
+		return x.RoundsPerSeason
+	}
+	return 0
+}
+
+func (x *Campaign) GetEveryFaction() bool {
+	if x != nil {
+		return x.EveryFaction
+	}
+	return false
+}
+
+func (x *Campaign) GetConfig() *MatchConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+func (x *Campaign) GetGames() []*CampaignGame {
+	if x != nil {
+		return x.Games
+	}
+	return nil
+}
+
+type CampaignGame struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Season int32 `protobuf:"varint,1,opt,name=Season,proto3" json:"Season,omitempty"`
+	Round  int32 `protobuf:"varint,2,opt,name=Round,proto3" json:"Round,omitempty"`
+	// Players sit at the game, each playing the match's player at the same
+	// index once the match is generated.
+	Players []string `protobuf:"bytes,3,rep,name=Players,proto3" json:"Players,omitempty"`
+	MatchId string   `protobuf:"bytes,4,opt,name=MatchId,proto3" json:"MatchId,omitempty"`
+}
+
+func (x *CampaignGame) Reset() {
+	*x = CampaignGame{}
+	mi := &file_match_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CampaignGame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CampaignGame) ProtoMessage() {}
+
+func (x *CampaignGame) ProtoReflect() protoreflect.Message {
+	mi := &file_match_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CampaignGame.ProtoReflect.Descriptor instead.
+func (*CampaignGame) Descriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *CampaignGame) GetSeason() int32 {
+	if x != nil {
+		return x.Season
+	}
+	return 0
+}
+
+func (x *CampaignGame) GetRound() int32 {
+	if x != nil {
+		return x.Round
+	}
+	return 0
+}
+
+func (x *CampaignGame) GetPlayers() []string {
+	if x != nil {
+		return x.Players
+	}
+	return nil
+}
+
+func (x *CampaignGame) GetMatchId() string {
+	if x != nil {
+		return x.MatchId
+	}
+	return ""
+}
+
+type GenerateMatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Config *MatchConfig `protobuf:"bytes,1,opt,name=Config,proto3" json:"Config,omitempty"`
+}
+
+func (x *GenerateMatchRequest) Reset() {
+	*x = GenerateMatchRequest{}
+	mi := &file_match_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerateMatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerateMatchRequest) ProtoMessage() {}
+
+func (x *GenerateMatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_match_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerateMatchRequest.ProtoReflect.Descriptor instead.
+func (*GenerateMatchRequest) Descriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GenerateMatchRequest) GetConfig() *MatchConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
+type GetMatchRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id string `protobuf:"bytes,1,opt,name=Id,proto3" json:"Id,omitempty"`
+}
+
+func (x *GetMatchRequest) Reset() {
+	*x = GetMatchRequest{}
+	mi := &file_match_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMatchRequest) ProtoMessage() {}
+
+func (x *GetMatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_match_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMatchRequest.ProtoReflect.Descriptor instead.
+func (*GetMatchRequest) Descriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *GetMatchRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type ListMatchesRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Limit caps the number of matches returned, newest first. 0 lists all.
+	Limit int32 `protobuf:"varint,1,opt,name=Limit,proto3" json:"Limit,omitempty"`
+}
+
+func (x *ListMatchesRequest) Reset() {
+	*x = ListMatchesRequest{}
+	mi := &file_match_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMatchesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMatchesRequest) ProtoMessage() {}
+
+func (x *ListMatchesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_match_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMatchesRequest.ProtoReflect.Descriptor instead.
+func (*ListMatchesRequest) Descriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *ListMatchesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+type ListMatchesResponse struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Matches []*MatchRecord `protobuf:"bytes,1,rep,name=Matches,proto3" json:"Matches,omitempty"`
+}
+
+func (x *ListMatchesResponse) Reset() {
+	*x = ListMatchesResponse{}
+	mi := &file_match_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListMatchesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListMatchesResponse) ProtoMessage() {}
+
+func (x *ListMatchesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_match_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListMatchesResponse.ProtoReflect.Descriptor instead.
+func (*ListMatchesResponse) Descriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *ListMatchesResponse) GetMatches() []*MatchRecord {
+	if x != nil {
+		return x.Matches
+	}
+	return nil
+}
+
+type RecordResultRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id      string    `protobuf:"bytes,1,opt,name=Id,proto3" json:"Id,omitempty"`
+	Results []*Result `protobuf:"bytes,2,rep,name=Results,proto3" json:"Results,omitempty"`
+}
+
+func (x *RecordResultRequest) Reset() {
+	*x = RecordResultRequest{}
+	mi := &file_match_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RecordResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RecordResultRequest) ProtoMessage() {}
+
+func (x *RecordResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_match_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RecordResultRequest.ProtoReflect.Descriptor instead.
+func (*RecordResultRequest) Descriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *RecordResultRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RecordResultRequest) GetResults() []*Result {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type RerollComponentRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Id        string       `protobuf:"bytes,1,opt,name=Id,proto3" json:"Id,omitempty"`
+	Component Component    `protobuf:"varint,2,opt,name=Component,proto3,enum=match.Component" json:"Component,omitempty"`
+	Config    *MatchConfig `protobuf:"bytes,3,opt,name=Config,proto3" json:"Config,omitempty"`
+}
+
+func (x *RerollComponentRequest) Reset() {
+	*x = RerollComponentRequest{}
+	mi := &file_match_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RerollComponentRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RerollComponentRequest) ProtoMessage() {}
+
+func (x *RerollComponentRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_match_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RerollComponentRequest.ProtoReflect.Descriptor instead.
+func (*RerollComponentRequest) Descriptor() ([]byte, []int) {
+	return file_match_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *RerollComponentRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *RerollComponentRequest) GetComponent() Component {
+	if x != nil {
+		return x.Component
+	}
+	return Component_COMPONENT_UNSPECIFIED
+}
+
+func (x *RerollComponentRequest) GetConfig() *MatchConfig {
+	if x != nil {
+		return x.Config
+	}
+	return nil
+}
+
 var File_match_proto protoreflect.FileDescriptor
 
 var file_match_proto_rawDesc = []byte{
 	0x0a, 0x0b, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x6d,
-	0x61, 0x74, 0x63, 0x68, 0x22, 0xd3, 0x01, 0x0a, 0x05, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x28,
-	0x0a, 0x07, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x0e, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x46, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52,
-	0x07, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x22, 0x0a, 0x04, 0x42, 0x6f, 0x74, 0x73,
-	0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0e, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x46,
-	0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x04, 0x42, 0x6f, 0x74, 0x73, 0x12, 0x2c, 0x0a, 0x09,
-	0x48, 0x69, 0x72, 0x65, 0x6c, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32,
-	0x0e, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x46, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x52,
-	0x09, 0x48, 0x69, 0x72, 0x65, 0x6c, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x1f, 0x0a, 0x03, 0x4d, 0x61,
-	0x70, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e,
-	0x4d, 0x61, 0x70, 0x56, 0x61, 0x6c, 0x52, 0x03, 0x4d, 0x61, 0x70, 0x12, 0x2d, 0x0a, 0x09, 0x4c,
-	0x61, 0x6e, 0x64, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f,
-	0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4c, 0x61, 0x6e, 0x64, 0x6d, 0x61, 0x72, 0x6b, 0x52,
-	0x09, 0x4c, 0x61, 0x6e, 0x64, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x22, 0x40, 0x0a, 0x06, 0x4d, 0x61,
-	0x70, 0x56, 0x61, 0x6c, 0x12, 0x22, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01,
-	0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4d, 0x61, 0x70, 0x54, 0x79,
+	0x61, 0x74, 0x63, 0x68, 0x1a, 0x1f, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2f, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x84, 0x03, 0x0a, 0x05, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12,
+	0x28, 0x0a, 0x07, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b,
+	0x32, 0x0e, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x46, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x52, 0x07, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x1e, 0x0a, 0x04, 0x42, 0x6f, 0x74,
+	0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0a, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e,
+	0x42, 0x6f, 0x74, 0x52, 0x04, 0x42, 0x6f, 0x74, 0x73, 0x12, 0x2d, 0x0a, 0x09, 0x48, 0x69, 0x72,
+	0x65, 0x6c, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6d,
+	0x61, 0x74, 0x63, 0x68, 0x2e, 0x48, 0x69, 0x72, 0x65, 0x6c, 0x69, 0x6e, 0x67, 0x52, 0x09, 0x48,
+	0x69, 0x72, 0x65, 0x6c, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x1f, 0x0a, 0x03, 0x4d, 0x61, 0x70, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4d, 0x61,
+	0x70, 0x56, 0x61, 0x6c, 0x52, 0x03, 0x4d, 0x61, 0x70, 0x12, 0x2d, 0x0a, 0x09, 0x4c, 0x61, 0x6e,
+	0x64, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x18, 0x05, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6d,
+	0x61, 0x74, 0x63, 0x68, 0x2e, 0x4c, 0x61, 0x6e, 0x64, 0x6d, 0x61, 0x72, 0x6b, 0x52, 0x09, 0x4c,
+	0x61, 0x6e, 0x64, 0x6d, 0x61, 0x72, 0x6b, 0x73, 0x12, 0x2d, 0x0a, 0x09, 0x43, 0x6c, 0x65, 0x61,
+	0x72, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0f, 0x2e, 0x6d, 0x61,
+	0x74, 0x63, 0x68, 0x2e, 0x43, 0x6c, 0x65, 0x61, 0x72, 0x69, 0x6e, 0x67, 0x52, 0x09, 0x43, 0x6c,
+	0x65, 0x61, 0x72, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x1f, 0x0a, 0x04, 0x44, 0x65, 0x63, 0x6b, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x44, 0x65,
+	0x63, 0x6b, 0x52, 0x04, 0x44, 0x65, 0x63, 0x6b, 0x12, 0x21, 0x0a, 0x05, 0x53, 0x65, 0x61, 0x74,
+	0x73, 0x18, 0x08, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e,
+	0x53, 0x65, 0x61, 0x74, 0x52, 0x05, 0x53, 0x65, 0x61, 0x74, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x46,
+	0x69, 0x72, 0x73, 0x74, 0x53, 0x65, 0x61, 0x74, 0x18, 0x09, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09,
+	0x46, 0x69, 0x72, 0x73, 0x74, 0x53, 0x65, 0x61, 0x74, 0x12, 0x21, 0x0a, 0x05, 0x48, 0x6f, 0x6d,
+	0x65, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0b, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68,
+	0x2e, 0x48, 0x6f, 0x6d, 0x65, 0x52, 0x05, 0x48, 0x6f, 0x6d, 0x65, 0x73, 0x22, 0xa4, 0x01, 0x0a,
+	0x0b, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x0e, 0x0a, 0x02,
+	0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x64, 0x12, 0x38, 0x0a, 0x09,
+	0x43, 0x72, 0x65, 0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x12, 0x22, 0x0a, 0x05, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x0c, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x05, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x27, 0x0a, 0x07, 0x52, 0x65,
+	0x73, 0x75, 0x6c, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6d, 0x61,
+	0x74, 0x63, 0x68, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x73, 0x22, 0xc8, 0x01, 0x0a, 0x08, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65,
+	0x12, 0x24, 0x0a, 0x0d, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x53, 0x63, 0x68, 0x65, 0x6d, 0x61, 0x56,
+	0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x2a, 0x0a, 0x10, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61,
+	0x74, 0x6f, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09,
+	0x52, 0x10, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x6f, 0x72, 0x56, 0x65, 0x72, 0x73, 0x69,
+	0x6f, 0x6e, 0x12, 0x12, 0x0a, 0x04, 0x53, 0x65, 0x65, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x03,
+	0x52, 0x04, 0x53, 0x65, 0x65, 0x64, 0x12, 0x2a, 0x0a, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67,
+	0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4d,
+	0x61, 0x74, 0x63, 0x68, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x43, 0x6f, 0x6e, 0x66,
+	0x69, 0x67, 0x12, 0x2a, 0x0a, 0x06, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x18, 0x05, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68,
+	0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x06, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x22, 0x7c,
+	0x0a, 0x06, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x50, 0x6c, 0x61, 0x79,
+	0x65, 0x72, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x12, 0x2c, 0x0a, 0x07, 0x46, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x46, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x07, 0x46, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x14,
+	0x0a, 0x05, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x53,
+	0x63, 0x6f, 0x72, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x57, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x08, 0x52, 0x06, 0x57, 0x69, 0x6e, 0x6e, 0x65, 0x72, 0x22, 0x81, 0x06, 0x0a,
+	0x0b, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x22, 0x0a, 0x0c,
+	0x55, 0x73, 0x65, 0x48, 0x69, 0x72, 0x65, 0x6c, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x08, 0x52, 0x0c, 0x55, 0x73, 0x65, 0x48, 0x69, 0x72, 0x65, 0x6c, 0x69, 0x6e, 0x67, 0x73,
+	0x12, 0x22, 0x0a, 0x0c, 0x55, 0x73, 0x65, 0x4c, 0x61, 0x6e, 0x64, 0x6d, 0x61, 0x72, 0x6b, 0x73,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0c, 0x55, 0x73, 0x65, 0x4c, 0x61, 0x6e, 0x64, 0x6d,
+	0x61, 0x72, 0x6b, 0x73, 0x12, 0x1e, 0x0a, 0x0a, 0x42, 0x6f, 0x74, 0x45, 0x6e, 0x65, 0x6d, 0x69,
+	0x65, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x42, 0x6f, 0x74, 0x45, 0x6e, 0x65,
+	0x6d, 0x69, 0x65, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73, 0x12, 0x3a,
+	0x0a, 0x0d, 0x4d, 0x69, 0x6e, 0x44, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x42, 0x6f,
+	0x74, 0x44, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x52, 0x0d, 0x4d, 0x69, 0x6e,
+	0x44, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x12, 0x3a, 0x0a, 0x0d, 0x4d, 0x61,
+	0x78, 0x44, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x18, 0x06, 0x20, 0x01, 0x28,
+	0x0e, 0x32, 0x14, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x42, 0x6f, 0x74, 0x44, 0x69, 0x66,
+	0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x52, 0x0d, 0x4d, 0x61, 0x78, 0x44, 0x69, 0x66, 0x66,
+	0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x12, 0x28, 0x0a, 0x0f, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74,
+	0x43, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x0f, 0x54, 0x61, 0x72, 0x67, 0x65, 0x74, 0x43, 0x68, 0x61, 0x6c, 0x6c, 0x65, 0x6e, 0x67, 0x65,
+	0x12, 0x22, 0x0a, 0x0c, 0x4d, 0x61, 0x78, 0x42, 0x6f, 0x74, 0x54, 0x72, 0x61, 0x69, 0x74, 0x73,
+	0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0c, 0x4d, 0x61, 0x78, 0x42, 0x6f, 0x74, 0x54, 0x72,
+	0x61, 0x69, 0x74, 0x73, 0x12, 0x1a, 0x0a, 0x08, 0x4d, 0x69, 0x6e, 0x53, 0x63, 0x6f, 0x72, 0x65,
+	0x18, 0x09, 0x20, 0x01, 0x28, 0x01, 0x52, 0x08, 0x4d, 0x69, 0x6e, 0x53, 0x63, 0x6f, 0x72, 0x65,
+	0x12, 0x1a, 0x0a, 0x08, 0x4d, 0x61, 0x78, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x18, 0x0a, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x08, 0x4d, 0x61, 0x78, 0x53, 0x63, 0x6f, 0x72, 0x65, 0x12, 0x2e, 0x0a, 0x08,
+	0x50, 0x61, 0x69, 0x72, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12,
+	0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x50, 0x61, 0x69, 0x72, 0x69, 0x6e, 0x67, 0x4d, 0x6f,
+	0x64, 0x65, 0x52, 0x08, 0x50, 0x61, 0x69, 0x72, 0x69, 0x6e, 0x67, 0x73, 0x12, 0x1e, 0x0a, 0x0a,
+	0x42, 0x61, 0x64, 0x50, 0x61, 0x69, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x0c, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x0a, 0x42, 0x61, 0x64, 0x50, 0x61, 0x69, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x26, 0x0a, 0x05,
+	0x53, 0x65, 0x74, 0x75, 0x70, 0x18, 0x0d, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x10, 0x2e, 0x6d, 0x61,
+	0x74, 0x63, 0x68, 0x2e, 0x53, 0x65, 0x74, 0x75, 0x70, 0x4d, 0x6f, 0x64, 0x65, 0x52, 0x05, 0x53,
+	0x65, 0x74, 0x75, 0x70, 0x12, 0x24, 0x0a, 0x0d, 0x55, 0x73, 0x65, 0x45, 0x78, 0x69, 0x6c, 0x65,
+	0x73, 0x44, 0x65, 0x63, 0x6b, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x55, 0x73, 0x65,
+	0x45, 0x78, 0x69, 0x6c, 0x65, 0x73, 0x44, 0x65, 0x63, 0x6b, 0x12, 0x31, 0x0a, 0x0a, 0x53, 0x65,
+	0x74, 0x75, 0x70, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x11,
+	0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x53, 0x65, 0x74, 0x75, 0x70, 0x4f, 0x72, 0x64, 0x65,
+	0x72, 0x52, 0x0a, 0x53, 0x65, 0x74, 0x75, 0x70, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x1a, 0x0a,
+	0x08, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x18, 0x10, 0x20, 0x01, 0x28, 0x08, 0x52,
+	0x08, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x12, 0x26, 0x0a, 0x0e, 0x43, 0x6f, 0x76,
+	0x65, 0x72, 0x61, 0x67, 0x65, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x18, 0x11, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x0e, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x61, 0x67, 0x65, 0x50, 0x6c, 0x61, 0x79, 0x65,
+	0x72, 0x12, 0x2e, 0x0a, 0x08, 0x46, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x12, 0x20,
+	0x03, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x46, 0x61, 0x63, 0x74,
+	0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x08, 0x46, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x73, 0x12, 0x2d, 0x0a, 0x07, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x65, 0x64, 0x18, 0x13, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x43, 0x6f, 0x76, 0x65, 0x72,
+	0x65, 0x64, 0x49, 0x74, 0x65, 0x6d, 0x73, 0x52, 0x07, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x65, 0x64,
+	0x22, 0x8e, 0x01, 0x0a, 0x0c, 0x43, 0x6f, 0x76, 0x65, 0x72, 0x65, 0x64, 0x49, 0x74, 0x65, 0x6d,
+	0x73, 0x12, 0x1a, 0x0a, 0x08, 0x46, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x18, 0x01, 0x20,
+	0x03, 0x28, 0x05, 0x52, 0x08, 0x46, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x73, 0x12, 0x12, 0x0a,
+	0x04, 0x42, 0x6f, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x05, 0x52, 0x04, 0x42, 0x6f, 0x74,
+	0x73, 0x12, 0x1c, 0x0a, 0x09, 0x48, 0x69, 0x72, 0x65, 0x6c, 0x69, 0x6e, 0x67, 0x73, 0x18, 0x03,
+	0x20, 0x03, 0x28, 0x05, 0x52, 0x09, 0x48, 0x69, 0x72, 0x65, 0x6c, 0x69, 0x6e, 0x67, 0x73, 0x12,
+	0x12, 0x0a, 0x04, 0x4d, 0x61, 0x70, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x05, 0x52, 0x04, 0x4d,
+	0x61, 0x70, 0x73, 0x12, 0x1c, 0x0a, 0x09, 0x4c, 0x61, 0x6e, 0x64, 0x6d, 0x61, 0x72, 0x6b, 0x73,
+	0x18, 0x05, 0x20, 0x03, 0x28, 0x05, 0x52, 0x09, 0x4c, 0x61, 0x6e, 0x64, 0x6d, 0x61, 0x72, 0x6b,
+	0x73, 0x22, 0x40, 0x0a, 0x06, 0x4d, 0x61, 0x70, 0x56, 0x61, 0x6c, 0x12, 0x22, 0x0a, 0x04, 0x54,
+	0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x6d, 0x61, 0x74, 0x63,
+	0x68, 0x2e, 0x4d, 0x61, 0x70, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e,
+	0x61, 0x6d, 0x65, 0x22, 0x47, 0x0a, 0x08, 0x4c, 0x61, 0x6e, 0x64, 0x6d, 0x61, 0x72, 0x6b, 0x12,
+	0x27, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4c, 0x61, 0x6e, 0x64, 0x6d, 0x61, 0x72, 0x6b, 0x54, 0x79,
 	0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x47, 0x0a, 0x08,
-	0x4c, 0x61, 0x6e, 0x64, 0x6d, 0x61, 0x72, 0x6b, 0x12, 0x27, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x13, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4c,
-	0x61, 0x6e, 0x64, 0x6d, 0x61, 0x72, 0x6b, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70,
-	0x65, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x04, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x45, 0x0a, 0x07, 0x46, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x45, 0x0a, 0x07,
+	0x46, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x46, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e,
+	0x61, 0x6d, 0x65, 0x22, 0xbd, 0x01, 0x0a, 0x03, 0x42, 0x6f, 0x74, 0x12, 0x26, 0x0a, 0x04, 0x54,
+	0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x74, 0x63,
+	0x68, 0x2e, 0x46, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x04, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x34, 0x0a, 0x0a, 0x44, 0x69, 0x66, 0x66, 0x69,
+	0x63, 0x75, 0x6c, 0x74, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x14, 0x2e, 0x6d, 0x61,
+	0x74, 0x63, 0x68, 0x2e, 0x42, 0x6f, 0x74, 0x44, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74,
+	0x79, 0x52, 0x0a, 0x44, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74, 0x79, 0x12, 0x16, 0x0a,
+	0x06, 0x54, 0x72, 0x61, 0x69, 0x74, 0x73, 0x18, 0x04, 0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x54,
+	0x72, 0x61, 0x69, 0x74, 0x73, 0x12, 0x2c, 0x0a, 0x09, 0x43, 0x6c, 0x6f, 0x63, 0x6b, 0x77, 0x6f,
+	0x72, 0x6b, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0e, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68,
+	0x2e, 0x42, 0x6f, 0x74, 0x54, 0x79, 0x70, 0x65, 0x52, 0x09, 0x43, 0x6c, 0x6f, 0x63, 0x6b, 0x77,
+	0x6f, 0x72, 0x6b, 0x22, 0x93, 0x01, 0x0a, 0x08, 0x48, 0x69, 0x72, 0x65, 0x6c, 0x69, 0x6e, 0x67,
 	0x12, 0x26, 0x0a, 0x04, 0x54, 0x79, 0x70, 0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12,
 	0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x46, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79,
 	0x70, 0x65, 0x52, 0x04, 0x54, 0x79, 0x70, 0x65, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x22, 0x43, 0x0a, 0x08,
-	0x43, 0x6c, 0x65, 0x61, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x1f, 0x0a, 0x04, 0x53, 0x75, 0x69, 0x74,
-	0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x53,
-	0x75, 0x69, 0x74, 0x52, 0x04, 0x53, 0x75, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x4e, 0x75, 0x6d,
-	0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x4e, 0x75, 0x6d, 0x62, 0x65,
-	0x72, 0x2a, 0xbb, 0x01, 0x0a, 0x0b, 0x46, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70,
-	0x65, 0x12, 0x0c, 0x0a, 0x08, 0x4d, 0x41, 0x52, 0x51, 0x55, 0x49, 0x53, 0x45, 0x10, 0x00, 0x12,
-	0x09, 0x0a, 0x05, 0x45, 0x59, 0x52, 0x49, 0x45, 0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x4c,
-	0x4c, 0x49, 0x41, 0x4e, 0x43, 0x45, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08, 0x56, 0x41, 0x47, 0x41,
-	0x42, 0x4f, 0x4e, 0x44, 0x10, 0x03, 0x12, 0x0d, 0x0a, 0x09, 0x52, 0x49, 0x56, 0x45, 0x52, 0x46,
-	0x4f, 0x4c, 0x4b, 0x10, 0x04, 0x12, 0x0a, 0x0a, 0x06, 0x4c, 0x49, 0x5a, 0x41, 0x52, 0x44, 0x10,
-	0x05, 0x12, 0x0f, 0x0a, 0x0b, 0x55, 0x4e, 0x44, 0x45, 0x52, 0x47, 0x52, 0x4f, 0x55, 0x4e, 0x44,
-	0x10, 0x08, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x4f, 0x52, 0x56, 0x49, 0x44, 0x10, 0x09, 0x12, 0x0c,
-	0x0a, 0x08, 0x48, 0x55, 0x4e, 0x44, 0x52, 0x45, 0x44, 0x53, 0x10, 0x0a, 0x12, 0x0b, 0x0a, 0x07,
-	0x4b, 0x45, 0x45, 0x50, 0x45, 0x52, 0x53, 0x10, 0x0b, 0x12, 0x0b, 0x0a, 0x07, 0x42, 0x41, 0x4e,
-	0x44, 0x49, 0x54, 0x53, 0x10, 0x0c, 0x12, 0x0d, 0x0a, 0x09, 0x50, 0x52, 0x4f, 0x54, 0x45, 0x43,
-	0x54, 0x4f, 0x52, 0x10, 0x0d, 0x12, 0x08, 0x0a, 0x04, 0x42, 0x41, 0x4e, 0x44, 0x10, 0x0e, 0x2a,
-	0x39, 0x0a, 0x07, 0x4d, 0x61, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0a, 0x0a, 0x06, 0x41, 0x55,
-	0x54, 0x55, 0x4d, 0x4e, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x57, 0x49, 0x4e, 0x54, 0x45, 0x52,
-	0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x4c, 0x41, 0x4b, 0x45, 0x10, 0x02, 0x12, 0x0c, 0x0a, 0x08,
-	0x4d, 0x4f, 0x55, 0x4e, 0x54, 0x41, 0x49, 0x4e, 0x10, 0x03, 0x2a, 0x52, 0x0a, 0x0c, 0x4c, 0x61,
-	0x6e, 0x64, 0x6d, 0x61, 0x72, 0x6b, 0x54, 0x79, 0x70, 0x65, 0x12, 0x09, 0x0a, 0x05, 0x54, 0x4f,
-	0x57, 0x45, 0x52, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x46, 0x45, 0x52, 0x52, 0x59, 0x10, 0x01,
-	0x12, 0x08, 0x0a, 0x04, 0x43, 0x49, 0x54, 0x59, 0x10, 0x02, 0x12, 0x09, 0x0a, 0x05, 0x46, 0x4f,
-	0x52, 0x47, 0x45, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x54, 0x52, 0x45, 0x45, 0x54, 0x4f, 0x50,
-	0x10, 0x04, 0x12, 0x0a, 0x0a, 0x06, 0x4d, 0x41, 0x52, 0x4b, 0x45, 0x54, 0x10, 0x05, 0x2a, 0x30,
-	0x0a, 0x04, 0x53, 0x75, 0x69, 0x74, 0x12, 0x08, 0x0a, 0x04, 0x42, 0x49, 0x52, 0x44, 0x10, 0x00,
-	0x12, 0x07, 0x0a, 0x03, 0x46, 0x4f, 0x58, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x4d, 0x4f, 0x55,
-	0x53, 0x45, 0x10, 0x02, 0x12, 0x0a, 0x0a, 0x06, 0x52, 0x41, 0x42, 0x42, 0x49, 0x54, 0x10, 0x03,
-	0x42, 0x27, 0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x67,
-	0x65, 0x63, 0x6b, 0x6f, 0x30, 0x35, 0x2f, 0x4c, 0x65, 0x67, 0x61, 0x63, 0x79, 0x52, 0x6f, 0x6f,
-	0x74, 0x2f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
-	0x33,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x1c, 0x0a, 0x09,
+	0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x09, 0x54, 0x68, 0x72, 0x65, 0x73, 0x68, 0x6f, 0x6c, 0x64, 0x12, 0x2d, 0x0a, 0x06, 0x53, 0x74,
+	0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x15, 0x2e, 0x6d, 0x61, 0x74,
+	0x63, 0x68, 0x2e, 0x48, 0x69, 0x72, 0x65, 0x6c, 0x69, 0x6e, 0x67, 0x53, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x52, 0x06, 0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x22, 0x66, 0x0a, 0x04, 0x53, 0x65, 0x61,
+	0x74, 0x12, 0x2c, 0x0a, 0x07, 0x46, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x46, 0x61, 0x63, 0x74, 0x69,
+	0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x07, 0x46, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12,
+	0x10, 0x0a, 0x03, 0x42, 0x6f, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x08, 0x52, 0x03, 0x42, 0x6f,
+	0x74, 0x12, 0x1e, 0x0a, 0x0a, 0x53, 0x65, 0x74, 0x75, 0x70, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x18,
+	0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0a, 0x53, 0x65, 0x74, 0x75, 0x70, 0x4f, 0x72, 0x64, 0x65,
+	0x72, 0x22, 0x50, 0x0a, 0x04, 0x48, 0x6f, 0x6d, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x46, 0x61, 0x63,
+	0x74, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x74,
+	0x63, 0x68, 0x2e, 0x46, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x52, 0x07,
+	0x46, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x43, 0x6c, 0x65, 0x61, 0x72,
+	0x69, 0x6e, 0x67, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x08, 0x43, 0x6c, 0x65, 0x61, 0x72,
+	0x69, 0x6e, 0x67, 0x22, 0x43, 0x0a, 0x08, 0x43, 0x6c, 0x65, 0x61, 0x72, 0x69, 0x6e, 0x67, 0x12,
+	0x1f, 0x0a, 0x04, 0x53, 0x75, 0x69, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x0b, 0x2e,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x53, 0x75, 0x69, 0x74, 0x52, 0x04, 0x53, 0x75, 0x69, 0x74,
+	0x12, 0x16, 0x0a, 0x06, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x06, 0x4e, 0x75, 0x6d, 0x62, 0x65, 0x72, 0x22, 0xdd, 0x02, 0x0a, 0x08, 0x43, 0x61, 0x6d,
+	0x70, 0x61, 0x69, 0x67, 0x6e, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x49, 0x64, 0x12, 0x12, 0x0a, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x04, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x38, 0x0a, 0x09, 0x43, 0x72, 0x65,
+	0x61, 0x74, 0x65, 0x64, 0x41, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67,
+	0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54,
+	0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x52, 0x09, 0x43, 0x72, 0x65, 0x61, 0x74, 0x65,
+	0x64, 0x41, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x52, 0x6f, 0x73, 0x74, 0x65, 0x72, 0x18, 0x04, 0x20,
+	0x03, 0x28, 0x09, 0x52, 0x06, 0x52, 0x6f, 0x73, 0x74, 0x65, 0x72, 0x12, 0x18, 0x0a, 0x07, 0x53,
+	0x63, 0x6f, 0x72, 0x69, 0x6e, 0x67, 0x18, 0x05, 0x20, 0x03, 0x28, 0x05, 0x52, 0x07, 0x53, 0x63,
+	0x6f, 0x72, 0x69, 0x6e, 0x67, 0x12, 0x1c, 0x0a, 0x09, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x53, 0x69,
+	0x7a, 0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x09, 0x54, 0x61, 0x62, 0x6c, 0x65, 0x53,
+	0x69, 0x7a, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x73, 0x50, 0x65, 0x72,
+	0x53, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x18, 0x07, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0f, 0x52, 0x6f,
+	0x75, 0x6e, 0x64, 0x73, 0x50, 0x65, 0x72, 0x53, 0x65, 0x61, 0x73, 0x6f, 0x6e, 0x12, 0x22, 0x0a,
+	0x0c, 0x45, 0x76, 0x65, 0x72, 0x79, 0x46, 0x61, 0x63, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x08, 0x20,
+	0x01, 0x28, 0x08, 0x52, 0x0c, 0x45, 0x76, 0x65, 0x72, 0x79, 0x46, 0x61, 0x63, 0x74, 0x69, 0x6f,
+	0x6e, 0x12, 0x2a, 0x0a, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x43,
+	0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x12, 0x29, 0x0a,
+	0x05, 0x47, 0x61, 0x6d, 0x65, 0x73, 0x18, 0x0a, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x13, 0x2e, 0x6d,
+	0x61, 0x74, 0x63, 0x68, 0x2e, 0x43, 0x61, 0x6d, 0x70, 0x61, 0x69, 0x67, 0x6e, 0x47, 0x61, 0x6d,
+	0x65, 0x52, 0x05, 0x47, 0x61, 0x6d, 0x65, 0x73, 0x22, 0x70, 0x0a, 0x0c, 0x43, 0x61, 0x6d, 0x70,
+	0x61, 0x69, 0x67, 0x6e, 0x47, 0x61, 0x6d, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x65, 0x61, 0x73,
+	0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x53, 0x65, 0x61, 0x73, 0x6f, 0x6e,
+	0x12, 0x14, 0x0a, 0x05, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52,
+	0x05, 0x52, 0x6f, 0x75, 0x6e, 0x64, 0x12, 0x18, 0x0a, 0x07, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72,
+	0x73, 0x18, 0x03, 0x20, 0x03, 0x28, 0x09, 0x52, 0x07, 0x50, 0x6c, 0x61, 0x79, 0x65, 0x72, 0x73,
+	0x12, 0x18, 0x0a, 0x07, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x49, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x07, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x49, 0x64, 0x22, 0x42, 0x0a, 0x14, 0x47, 0x65,
+	0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65,
+	0x73, 0x74, 0x12, 0x2a, 0x0a, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68,
+	0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x52, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x22, 0x21,
+	0x0a, 0x0f, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49,
+	0x64, 0x22, 0x2a, 0x0a, 0x12, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14, 0x0a, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x4c, 0x69, 0x6d, 0x69, 0x74, 0x22, 0x43, 0x0a,
+	0x13, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70,
+	0x6f, 0x6e, 0x73, 0x65, 0x12, 0x2c, 0x0a, 0x07, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x18,
+	0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x12, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4d, 0x61,
+	0x74, 0x63, 0x68, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x07, 0x4d, 0x61, 0x74, 0x63, 0x68,
+	0x65, 0x73, 0x22, 0x4e, 0x0a, 0x13, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x75,
+	0x6c, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x64, 0x12, 0x27, 0x0a, 0x07, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x0d, 0x2e, 0x6d, 0x61, 0x74,
+	0x63, 0x68, 0x2e, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x07, 0x52, 0x65, 0x73, 0x75, 0x6c,
+	0x74, 0x73, 0x22, 0x84, 0x01, 0x0a, 0x16, 0x52, 0x65, 0x72, 0x6f, 0x6c, 0x6c, 0x43, 0x6f, 0x6d,
+	0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x0e, 0x0a,
+	0x02, 0x49, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x49, 0x64, 0x12, 0x2e, 0x0a,
+	0x09, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0e,
+	0x32, 0x10, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65,
+	0x6e, 0x74, 0x52, 0x09, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x12, 0x2a, 0x0a,
+	0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x12, 0x2e,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x43, 0x6f, 0x6e, 0x66, 0x69,
+	0x67, 0x52, 0x06, 0x43, 0x6f, 0x6e, 0x66, 0x69, 0x67, 0x2a, 0xbb, 0x01, 0x0a, 0x0b, 0x46, 0x61,
+	0x63, 0x74, 0x69, 0x6f, 0x6e, 0x54, 0x79, 0x70, 0x65, 0x12, 0x0c, 0x0a, 0x08, 0x4d, 0x41, 0x52,
+	0x51, 0x55, 0x49, 0x53, 0x45, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x45, 0x59, 0x52, 0x49, 0x45,
+	0x10, 0x01, 0x12, 0x0c, 0x0a, 0x08, 0x41, 0x4c, 0x4c, 0x49, 0x41, 0x4e, 0x43, 0x45, 0x10, 0x02,
+	0x12, 0x0c, 0x0a, 0x08, 0x56, 0x41, 0x47, 0x41, 0x42, 0x4f, 0x4e, 0x44, 0x10, 0x03, 0x12, 0x0d,
+	0x0a, 0x09, 0x52, 0x49, 0x56, 0x45, 0x52, 0x46, 0x4f, 0x4c, 0x4b, 0x10, 0x04, 0x12, 0x0a, 0x0a,
+	0x06, 0x4c, 0x49, 0x5a, 0x41, 0x52, 0x44, 0x10, 0x05, 0x12, 0x0f, 0x0a, 0x0b, 0x55, 0x4e, 0x44,
+	0x45, 0x52, 0x47, 0x52, 0x4f, 0x55, 0x4e, 0x44, 0x10, 0x08, 0x12, 0x0a, 0x0a, 0x06, 0x43, 0x4f,
+	0x52, 0x56, 0x49, 0x44, 0x10, 0x09, 0x12, 0x0c, 0x0a, 0x08, 0x48, 0x55, 0x4e, 0x44, 0x52, 0x45,
+	0x44, 0x53, 0x10, 0x0a, 0x12, 0x0b, 0x0a, 0x07, 0x4b, 0x45, 0x45, 0x50, 0x45, 0x52, 0x53, 0x10,
+	0x0b, 0x12, 0x0b, 0x0a, 0x07, 0x42, 0x41, 0x4e, 0x44, 0x49, 0x54, 0x53, 0x10, 0x0c, 0x12, 0x0d,
+	0x0a, 0x09, 0x50, 0x52, 0x4f, 0x54, 0x45, 0x43, 0x54, 0x4f, 0x52, 0x10, 0x0d, 0x12, 0x08, 0x0a,
+	0x04, 0x42, 0x41, 0x4e, 0x44, 0x10, 0x0e, 0x2a, 0xca, 0x01, 0x0a, 0x07, 0x42, 0x6f, 0x74, 0x54,
+	0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x14, 0x42, 0x4f, 0x54, 0x5f, 0x54, 0x59, 0x50, 0x45, 0x5f,
+	0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x17, 0x0a,
+	0x13, 0x4d, 0x45, 0x43, 0x48, 0x41, 0x4e, 0x49, 0x43, 0x41, 0x4c, 0x5f, 0x4d, 0x41, 0x52, 0x51,
+	0x55, 0x49, 0x53, 0x45, 0x10, 0x0a, 0x12, 0x12, 0x0a, 0x0e, 0x45, 0x4c, 0x45, 0x43, 0x54, 0x52,
+	0x49, 0x43, 0x5f, 0x45, 0x59, 0x52, 0x49, 0x45, 0x10, 0x01, 0x12, 0x16, 0x0a, 0x12, 0x41, 0x55,
+	0x54, 0x4f, 0x4d, 0x41, 0x54, 0x45, 0x44, 0x5f, 0x41, 0x4c, 0x4c, 0x49, 0x41, 0x4e, 0x43, 0x45,
+	0x10, 0x02, 0x12, 0x0b, 0x0a, 0x07, 0x56, 0x41, 0x47, 0x41, 0x42, 0x4f, 0x54, 0x10, 0x03, 0x12,
+	0x14, 0x0a, 0x10, 0x52, 0x49, 0x56, 0x45, 0x52, 0x46, 0x4f, 0x4c, 0x4b, 0x5f, 0x52, 0x4f, 0x42,
+	0x4f, 0x54, 0x53, 0x10, 0x04, 0x12, 0x13, 0x0a, 0x0f, 0x4c, 0x4f, 0x47, 0x49, 0x43, 0x41, 0x4c,
+	0x5f, 0x4c, 0x49, 0x5a, 0x41, 0x52, 0x44, 0x53, 0x10, 0x05, 0x12, 0x12, 0x0a, 0x0e, 0x44, 0x52,
+	0x49, 0x4c, 0x4c, 0x42, 0x49, 0x54, 0x5f, 0x44, 0x55, 0x43, 0x48, 0x59, 0x10, 0x08, 0x12, 0x14,
+	0x0a, 0x10, 0x43, 0x4f, 0x47, 0x57, 0x48, 0x45, 0x45, 0x4c, 0x5f, 0x43, 0x4f, 0x52, 0x56, 0x49,
+	0x44, 0x53, 0x10, 0x09, 0x2a, 0x39, 0x0a, 0x07, 0x4d, 0x61, 0x70, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x0a, 0x0a, 0x06, 0x41, 0x55, 0x54, 0x55, 0x4d, 0x4e, 0x10, 0x00, 0x12, 0x0a, 0x0a, 0x06, 0x57,
+	0x49, 0x4e, 0x54, 0x45, 0x52, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x4c, 0x41, 0x4b, 0x45, 0x10,
+	0x02, 0x12, 0x0c, 0x0a, 0x08, 0x4d, 0x4f, 0x55, 0x4e, 0x54, 0x41, 0x49, 0x4e, 0x10, 0x03, 0x2a,
+	0x52, 0x0a, 0x0c, 0x4c, 0x61, 0x6e, 0x64, 0x6d, 0x61, 0x72, 0x6b, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x09, 0x0a, 0x05, 0x54, 0x4f, 0x57, 0x45, 0x52, 0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x46, 0x45,
+	0x52, 0x52, 0x59, 0x10, 0x01, 0x12, 0x08, 0x0a, 0x04, 0x43, 0x49, 0x54, 0x59, 0x10, 0x02, 0x12,
+	0x09, 0x0a, 0x05, 0x46, 0x4f, 0x52, 0x47, 0x45, 0x10, 0x03, 0x12, 0x0b, 0x0a, 0x07, 0x54, 0x52,
+	0x45, 0x45, 0x54, 0x4f, 0x50, 0x10, 0x04, 0x12, 0x0a, 0x0a, 0x06, 0x4d, 0x41, 0x52, 0x4b, 0x45,
+	0x54, 0x10, 0x05, 0x2a, 0x2b, 0x0a, 0x0e, 0x48, 0x69, 0x72, 0x65, 0x6c, 0x69, 0x6e, 0x67, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x0c, 0x0a, 0x08, 0x50, 0x52, 0x4f, 0x4d, 0x4f, 0x54, 0x45,
+	0x44, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x44, 0x45, 0x4d, 0x4f, 0x54, 0x45, 0x44, 0x10, 0x01,
+	0x2a, 0x46, 0x0a, 0x0d, 0x42, 0x6f, 0x74, 0x44, 0x69, 0x66, 0x66, 0x69, 0x63, 0x75, 0x6c, 0x74,
+	0x79, 0x12, 0x08, 0x0a, 0x04, 0x45, 0x41, 0x53, 0x59, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x44,
+	0x45, 0x46, 0x41, 0x55, 0x4c, 0x54, 0x10, 0x01, 0x12, 0x0f, 0x0a, 0x0b, 0x43, 0x48, 0x41, 0x4c,
+	0x4c, 0x45, 0x4e, 0x47, 0x49, 0x4e, 0x47, 0x10, 0x02, 0x12, 0x0d, 0x0a, 0x09, 0x4e, 0x49, 0x47,
+	0x48, 0x54, 0x4d, 0x41, 0x52, 0x45, 0x10, 0x03, 0x2a, 0x50, 0x0a, 0x0b, 0x50, 0x61, 0x69, 0x72,
+	0x69, 0x6e, 0x67, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x12, 0x0a, 0x0e, 0x41, 0x4c, 0x4c, 0x4f, 0x57,
+	0x5f, 0x50, 0x41, 0x49, 0x52, 0x49, 0x4e, 0x47, 0x53, 0x10, 0x00, 0x12, 0x18, 0x0a, 0x14, 0x44,
+	0x4f, 0x57, 0x4e, 0x5f, 0x57, 0x45, 0x49, 0x47, 0x48, 0x54, 0x5f, 0x50, 0x41, 0x49, 0x52, 0x49,
+	0x4e, 0x47, 0x53, 0x10, 0x01, 0x12, 0x13, 0x0a, 0x0f, 0x46, 0x4f, 0x52, 0x42, 0x49, 0x44, 0x5f,
+	0x50, 0x41, 0x49, 0x52, 0x49, 0x4e, 0x47, 0x53, 0x10, 0x02, 0x2a, 0x41, 0x0a, 0x04, 0x44, 0x65,
+	0x63, 0x6b, 0x12, 0x10, 0x0a, 0x0c, 0x55, 0x4e, 0x4b, 0x4e, 0x4f, 0x57, 0x4e, 0x5f, 0x44, 0x45,
+	0x43, 0x4b, 0x10, 0x00, 0x12, 0x0d, 0x0a, 0x09, 0x42, 0x41, 0x53, 0x45, 0x5f, 0x44, 0x45, 0x43,
+	0x4b, 0x10, 0x01, 0x12, 0x18, 0x0a, 0x14, 0x45, 0x58, 0x49, 0x4c, 0x45, 0x53, 0x5f, 0x41, 0x4e,
+	0x44, 0x5f, 0x50, 0x41, 0x52, 0x54, 0x49, 0x53, 0x41, 0x4e, 0x53, 0x10, 0x02, 0x2a, 0x32, 0x0a,
+	0x0a, 0x53, 0x65, 0x74, 0x75, 0x70, 0x4f, 0x72, 0x64, 0x65, 0x72, 0x12, 0x0e, 0x0a, 0x0a, 0x54,
+	0x55, 0x52, 0x4e, 0x5f, 0x4f, 0x52, 0x44, 0x45, 0x52, 0x10, 0x00, 0x12, 0x14, 0x0a, 0x10, 0x46,
+	0x41, 0x43, 0x54, 0x49, 0x4f, 0x4e, 0x5f, 0x50, 0x52, 0x49, 0x4f, 0x52, 0x49, 0x54, 0x59, 0x10,
+	0x01, 0x2a, 0x2a, 0x0a, 0x09, 0x53, 0x65, 0x74, 0x75, 0x70, 0x4d, 0x6f, 0x64, 0x65, 0x12, 0x12,
+	0x0a, 0x0e, 0x53, 0x54, 0x41, 0x4e, 0x44, 0x41, 0x52, 0x44, 0x5f, 0x53, 0x45, 0x54, 0x55, 0x50,
+	0x10, 0x00, 0x12, 0x09, 0x0a, 0x05, 0x41, 0x44, 0x53, 0x45, 0x54, 0x10, 0x01, 0x2a, 0x79, 0x0a,
+	0x09, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x12, 0x19, 0x0a, 0x15, 0x43, 0x4f,
+	0x4d, 0x50, 0x4f, 0x4e, 0x45, 0x4e, 0x54, 0x5f, 0x55, 0x4e, 0x53, 0x50, 0x45, 0x43, 0x49, 0x46,
+	0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x0b, 0x0a, 0x07, 0x50, 0x4c, 0x41, 0x59, 0x45, 0x52, 0x53,
+	0x10, 0x07, 0x12, 0x08, 0x0a, 0x04, 0x42, 0x4f, 0x54, 0x53, 0x10, 0x01, 0x12, 0x0d, 0x0a, 0x09,
+	0x48, 0x49, 0x52, 0x45, 0x4c, 0x49, 0x4e, 0x47, 0x53, 0x10, 0x02, 0x12, 0x07, 0x0a, 0x03, 0x4d,
+	0x41, 0x50, 0x10, 0x03, 0x12, 0x0d, 0x0a, 0x09, 0x4c, 0x41, 0x4e, 0x44, 0x4d, 0x41, 0x52, 0x4b,
+	0x53, 0x10, 0x04, 0x12, 0x08, 0x0a, 0x04, 0x44, 0x45, 0x43, 0x4b, 0x10, 0x05, 0x12, 0x09, 0x0a,
+	0x05, 0x53, 0x45, 0x41, 0x54, 0x53, 0x10, 0x06, 0x2a, 0x30, 0x0a, 0x04, 0x53, 0x75, 0x69, 0x74,
+	0x12, 0x08, 0x0a, 0x04, 0x42, 0x49, 0x52, 0x44, 0x10, 0x00, 0x12, 0x07, 0x0a, 0x03, 0x46, 0x4f,
+	0x58, 0x10, 0x01, 0x12, 0x09, 0x0a, 0x05, 0x4d, 0x4f, 0x55, 0x53, 0x45, 0x10, 0x02, 0x12, 0x0a,
+	0x0a, 0x06, 0x52, 0x41, 0x42, 0x42, 0x49, 0x54, 0x10, 0x03, 0x32, 0xd4, 0x02, 0x0a, 0x0c, 0x4d,
+	0x61, 0x74, 0x63, 0x68, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x40, 0x0a, 0x0d, 0x47,
+	0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x1b, 0x2e, 0x6d,
+	0x61, 0x74, 0x63, 0x68, 0x2e, 0x47, 0x65, 0x6e, 0x65, 0x72, 0x61, 0x74, 0x65, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x6d, 0x61, 0x74, 0x63,
+	0x68, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x36, 0x0a,
+	0x08, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x12, 0x16, 0x2e, 0x6d, 0x61, 0x74, 0x63,
+	0x68, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73,
+	0x74, 0x1a, 0x12, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x44, 0x0a, 0x0b, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x61, 0x74,
+	0x63, 0x68, 0x65, 0x73, 0x12, 0x19, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4c, 0x69, 0x73,
+	0x74, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x65, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a,
+	0x1a, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4c, 0x69, 0x73, 0x74, 0x4d, 0x61, 0x74, 0x63,
+	0x68, 0x65, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x3e, 0x0a, 0x0c, 0x52,
+	0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x1a, 0x2e, 0x6d, 0x61,
+	0x74, 0x63, 0x68, 0x2e, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74,
+	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e,
+	0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x63, 0x6f, 0x72, 0x64, 0x12, 0x44, 0x0a, 0x0f, 0x52,
+	0x65, 0x72, 0x6f, 0x6c, 0x6c, 0x43, 0x6f, 0x6d, 0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x12, 0x1d,
+	0x2e, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x52, 0x65, 0x72, 0x6f, 0x6c, 0x6c, 0x43, 0x6f, 0x6d,
+	0x70, 0x6f, 0x6e, 0x65, 0x6e, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x12, 0x2e,
+	0x6d, 0x61, 0x74, 0x63, 0x68, 0x2e, 0x4d, 0x61, 0x74, 0x63, 0x68, 0x52, 0x65, 0x63, 0x6f, 0x72,
+	0x64, 0x42, 0x27, 0x5a, 0x25, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f,
+	0x67, 0x65, 0x63, 0x6b, 0x6f, 0x30, 0x35, 0x2f, 0x4c, 0x65, 0x67, 0x61, 0x63, 0x79, 0x52, 0x6f,
+	0x6f, 0x74, 0x2f, 0x6d, 0x61, 0x74, 0x63, 0x68, 0x70, 0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74,
+	0x6f, 0x33,
 }
 
 var (
@@ -627,34 +2612,102 @@ func file_match_proto_rawDescGZIP() []byte {
 	return file_match_proto_rawDescData
 }
 
-var file_match_proto_enumTypes = make([]protoimpl.EnumInfo, 4)
-var file_match_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_match_proto_enumTypes = make([]protoimpl.EnumInfo, 12)
+var file_match_proto_msgTypes = make([]protoimpl.MessageInfo, 22)
 var file_match_proto_goTypes = []any{
-	(FactionType)(0),  // 0: match.FactionType
-	(MapType)(0),      // 1: match.MapType
-	(LandmarkType)(0), // 2: match.LandmarkType
-	(Suit)(0),         // 3: match.Suit
-	(*Match)(nil),     // 4: match.Match
-	(*MapVal)(nil),    // 5: match.MapVal
-	(*Landmark)(nil),  // 6: match.Landmark
-	(*Faction)(nil),   // 7: match.Faction
-	(*Clearing)(nil),  // 8: match.Clearing
+	(FactionType)(0),               // 0: match.FactionType
+	(BotType)(0),                   // 1: match.BotType
+	(MapType)(0),                   // 2: match.MapType
+	(LandmarkType)(0),              // 3: match.LandmarkType
+	(HirelingStatus)(0),            // 4: match.HirelingStatus
+	(BotDifficulty)(0),             // 5: match.BotDifficulty
+	(PairingMode)(0),               // 6: match.PairingMode
+	(Deck)(0),                      // 7: match.Deck
+	(SetupOrder)(0),                // 8: match.SetupOrder
+	(SetupMode)(0),                 // 9: match.SetupMode
+	(Component)(0),                 // 10: match.Component
+	(Suit)(0),                      // 11: match.Suit
+	(*Match)(nil),                  // 12: match.Match
+	(*MatchRecord)(nil),            // 13: match.MatchRecord
+	(*Envelope)(nil),               // 14: match.Envelope
+	(*Result)(nil),                 // 15: match.Result
+	(*MatchConfig)(nil),            // 16: match.MatchConfig
+	(*CoveredItems)(nil),           // 17: match.CoveredItems
+	(*MapVal)(nil),                 // 18: match.MapVal
+	(*Landmark)(nil),               // 19: match.Landmark
+	(*Faction)(nil),                // 20: match.Faction
+	(*Bot)(nil),                    // 21: match.Bot
+	(*Hireling)(nil),               // 22: match.Hireling
+	(*Seat)(nil),                   // 23: match.Seat
+	(*Home)(nil),                   // 24: match.Home
+	(*Clearing)(nil),               // 25: match.Clearing
+	(*Campaign)(nil),               // 26: match.Campaign
+	(*CampaignGame)(nil),           // 27: match.CampaignGame
+	(*GenerateMatchRequest)(nil),   // 28: match.GenerateMatchRequest
+	(*GetMatchRequest)(nil),        // 29: match.GetMatchRequest
+	(*ListMatchesRequest)(nil),     // 30: match.ListMatchesRequest
+	(*ListMatchesResponse)(nil),    // 31: match.ListMatchesResponse
+	(*RecordResultRequest)(nil),    // 32: match.RecordResultRequest
+	(*RerollComponentRequest)(nil), // 33: match.RerollComponentRequest
+	(*timestamppb.Timestamp)(nil),  // 34: google.protobuf.Timestamp
 }
 var file_match_proto_depIdxs = []int32{
-	7, // 0: match.Match.Players:type_name -> match.Faction
-	7, // 1: match.Match.Bots:type_name -> match.Faction
-	7, // 2: match.Match.Hirelings:type_name -> match.Faction
-	5, // 3: match.Match.Map:type_name -> match.MapVal
-	6, // 4: match.Match.Landmarks:type_name -> match.Landmark
-	1, // 5: match.MapVal.Type:type_name -> match.MapType
-	2, // 6: match.Landmark.Type:type_name -> match.LandmarkType
-	0, // 7: match.Faction.Type:type_name -> match.FactionType
-	3, // 8: match.Clearing.Suit:type_name -> match.Suit
-	9, // [9:9] is the sub-list for method output_type
-	9, // [9:9] is the sub-list for method input_type
-	9, // [9:9] is the sub-list for extension type_name
-	9, // [9:9] is the sub-list for extension extendee
-	0, // [0:9] is the sub-list for field type_name
+	20, // 0: match.Match.Players:type_name -> match.Faction
+	21, // 1: match.Match.Bots:type_name -> match.Bot
+	22, // 2: match.Match.Hirelings:type_name -> match.Hireling
+	18, // 3: match.Match.Map:type_name -> match.MapVal
+	19, // 4: match.Match.Landmarks:type_name -> match.Landmark
+	25, // 5: match.Match.Clearings:type_name -> match.Clearing
+	7,  // 6: match.Match.Deck:type_name -> match.Deck
+	23, // 7: match.Match.Seats:type_name -> match.Seat
+	24, // 8: match.Match.Homes:type_name -> match.Home
+	34, // 9: match.MatchRecord.CreatedAt:type_name -> google.protobuf.Timestamp
+	12, // 10: match.MatchRecord.Match:type_name -> match.Match
+	15, // 11: match.MatchRecord.Results:type_name -> match.Result
+	16, // 12: match.Envelope.Config:type_name -> match.MatchConfig
+	13, // 13: match.Envelope.Record:type_name -> match.MatchRecord
+	0,  // 14: match.Result.Faction:type_name -> match.FactionType
+	5,  // 15: match.MatchConfig.MinDifficulty:type_name -> match.BotDifficulty
+	5,  // 16: match.MatchConfig.MaxDifficulty:type_name -> match.BotDifficulty
+	6,  // 17: match.MatchConfig.Pairings:type_name -> match.PairingMode
+	9,  // 18: match.MatchConfig.Setup:type_name -> match.SetupMode
+	8,  // 19: match.MatchConfig.SetupOrder:type_name -> match.SetupOrder
+	0,  // 20: match.MatchConfig.Factions:type_name -> match.FactionType
+	17, // 21: match.MatchConfig.Covered:type_name -> match.CoveredItems
+	2,  // 22: match.MapVal.Type:type_name -> match.MapType
+	3,  // 23: match.Landmark.Type:type_name -> match.LandmarkType
+	0,  // 24: match.Faction.Type:type_name -> match.FactionType
+	0,  // 25: match.Bot.Type:type_name -> match.FactionType
+	5,  // 26: match.Bot.Difficulty:type_name -> match.BotDifficulty
+	1,  // 27: match.Bot.Clockwork:type_name -> match.BotType
+	0,  // 28: match.Hireling.Type:type_name -> match.FactionType
+	4,  // 29: match.Hireling.Status:type_name -> match.HirelingStatus
+	0,  // 30: match.Seat.Faction:type_name -> match.FactionType
+	0,  // 31: match.Home.Faction:type_name -> match.FactionType
+	11, // 32: match.Clearing.Suit:type_name -> match.Suit
+	34, // 33: match.Campaign.CreatedAt:type_name -> google.protobuf.Timestamp
+	16, // 34: match.Campaign.Config:type_name -> match.MatchConfig
+	27, // 35: match.Campaign.Games:type_name -> match.CampaignGame
+	16, // 36: match.GenerateMatchRequest.Config:type_name -> match.MatchConfig
+	13, // 37: match.ListMatchesResponse.Matches:type_name -> match.MatchRecord
+	15, // 38: match.RecordResultRequest.Results:type_name -> match.Result
+	10, // 39: match.RerollComponentRequest.Component:type_name -> match.Component
+	16, // 40: match.RerollComponentRequest.Config:type_name -> match.MatchConfig
+	28, // 41: match.MatchService.GenerateMatch:input_type -> match.GenerateMatchRequest
+	29, // 42: match.MatchService.GetMatch:input_type -> match.GetMatchRequest
+	30, // 43: match.MatchService.ListMatches:input_type -> match.ListMatchesRequest
+	32, // 44: match.MatchService.RecordResult:input_type -> match.RecordResultRequest
+	33, // 45: match.MatchService.RerollComponent:input_type -> match.RerollComponentRequest
+	13, // 46: match.MatchService.GenerateMatch:output_type -> match.MatchRecord
+	13, // 47: match.MatchService.GetMatch:output_type -> match.MatchRecord
+	31, // 48: match.MatchService.ListMatches:output_type -> match.ListMatchesResponse
+	13, // 49: match.MatchService.RecordResult:output_type -> match.MatchRecord
+	13, // 50: match.MatchService.RerollComponent:output_type -> match.MatchRecord
+	46, // [46:51] is the sub-list for method output_type
+	41, // [41:46] is the sub-list for method input_type
+	41, // [41:41] is the sub-list for extension type_name
+	41, // [41:41] is the sub-list for extension extendee
+	0,  // [0:41] is the sub-list for field type_name
 }
 
 func init() { file_match_proto_init() }
@@ -667,10 +2720,10 @@ func file_match_proto_init() {
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_match_proto_rawDesc,
-			NumEnums:      4,
-			NumMessages:   5,
+			NumEnums:      12,
+			NumMessages:   22,
 			NumExtensions: 0,
-			NumServices:   0,
+			NumServices:   1,
 		},
 		GoTypes:           file_match_proto_goTypes,
 		DependencyIndexes: file_match_proto_depIdxs,
@@ -0,0 +1,273 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.1
+// source: match.proto
+
+package matchpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	MatchService_GenerateMatch_FullMethodName   = "/match.MatchService/GenerateMatch"
+	MatchService_GetMatch_FullMethodName        = "/match.MatchService/GetMatch"
+	MatchService_ListMatches_FullMethodName     = "/match.MatchService/ListMatches"
+	MatchService_RecordResult_FullMethodName    = "/match.MatchService/RecordResult"
+	MatchService_RerollComponent_FullMethodName = "/match.MatchService/RerollComponent"
+)
+
+// MatchServiceClient is the client API for MatchService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type MatchServiceClient interface {
+	GenerateMatch(ctx context.Context, in *GenerateMatchRequest, opts ...grpc.CallOption) (*MatchRecord, error)
+	GetMatch(ctx context.Context, in *GetMatchRequest, opts ...grpc.CallOption) (*MatchRecord, error)
+	ListMatches(ctx context.Context, in *ListMatchesRequest, opts ...grpc.CallOption) (*ListMatchesResponse, error)
+	RecordResult(ctx context.Context, in *RecordResultRequest, opts ...grpc.CallOption) (*MatchRecord, error)
+	RerollComponent(ctx context.Context, in *RerollComponentRequest, opts ...grpc.CallOption) (*MatchRecord, error)
+}
+
+type matchServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewMatchServiceClient(cc grpc.ClientConnInterface) MatchServiceClient {
+	return &matchServiceClient{cc}
+}
+
+func (c *matchServiceClient) GenerateMatch(ctx context.Context, in *GenerateMatchRequest, opts ...grpc.CallOption) (*MatchRecord, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MatchRecord)
+	err := c.cc.Invoke(ctx, MatchService_GenerateMatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *matchServiceClient) GetMatch(ctx context.Context, in *GetMatchRequest, opts ...grpc.CallOption) (*MatchRecord, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MatchRecord)
+	err := c.cc.Invoke(ctx, MatchService_GetMatch_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *matchServiceClient) ListMatches(ctx context.Context, in *ListMatchesRequest, opts ...grpc.CallOption) (*ListMatchesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListMatchesResponse)
+	err := c.cc.Invoke(ctx, MatchService_ListMatches_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *matchServiceClient) RecordResult(ctx context.Context, in *RecordResultRequest, opts ...grpc.CallOption) (*MatchRecord, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MatchRecord)
+	err := c.cc.Invoke(ctx, MatchService_RecordResult_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *matchServiceClient) RerollComponent(ctx context.Context, in *RerollComponentRequest, opts ...grpc.CallOption) (*MatchRecord, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MatchRecord)
+	err := c.cc.Invoke(ctx, MatchService_RerollComponent_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// MatchServiceServer is the server API for MatchService service.
+// All implementations must embed UnimplementedMatchServiceServer
+// for forward compatibility.
+type MatchServiceServer interface {
+	GenerateMatch(context.Context, *GenerateMatchRequest) (*MatchRecord, error)
+	GetMatch(context.Context, *GetMatchRequest) (*MatchRecord, error)
+	ListMatches(context.Context, *ListMatchesRequest) (*ListMatchesResponse, error)
+	RecordResult(context.Context, *RecordResultRequest) (*MatchRecord, error)
+	RerollComponent(context.Context, *RerollComponentRequest) (*MatchRecord, error)
+	mustEmbedUnimplementedMatchServiceServer()
+}
+
+// UnimplementedMatchServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedMatchServiceServer struct{}
+
+func (UnimplementedMatchServiceServer) GenerateMatch(context.Context, *GenerateMatchRequest) (*MatchRecord, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GenerateMatch not implemented")
+}
+func (UnimplementedMatchServiceServer) GetMatch(context.Context, *GetMatchRequest) (*MatchRecord, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetMatch not implemented")
+}
+func (UnimplementedMatchServiceServer) ListMatches(context.Context, *ListMatchesRequest) (*ListMatchesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListMatches not implemented")
+}
+func (UnimplementedMatchServiceServer) RecordResult(context.Context, *RecordResultRequest) (*MatchRecord, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RecordResult not implemented")
+}
+func (UnimplementedMatchServiceServer) RerollComponent(context.Context, *RerollComponentRequest) (*MatchRecord, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RerollComponent not implemented")
+}
+func (UnimplementedMatchServiceServer) mustEmbedUnimplementedMatchServiceServer() {}
+func (UnimplementedMatchServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeMatchServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to MatchServiceServer will
+// result in compilation errors.
+type UnsafeMatchServiceServer interface {
+	mustEmbedUnimplementedMatchServiceServer()
+}
+
+func RegisterMatchServiceServer(s grpc.ServiceRegistrar, srv MatchServiceServer) {
+	// If the following call pancis, it indicates UnimplementedMatchServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&MatchService_ServiceDesc, srv)
+}
+
+func _MatchService_GenerateMatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GenerateMatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatchServiceServer).GenerateMatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MatchService_GenerateMatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatchServiceServer).GenerateMatch(ctx, req.(*GenerateMatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MatchService_GetMatch_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetMatchRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatchServiceServer).GetMatch(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MatchService_GetMatch_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatchServiceServer).GetMatch(ctx, req.(*GetMatchRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MatchService_ListMatches_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListMatchesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatchServiceServer).ListMatches(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MatchService_ListMatches_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatchServiceServer).ListMatches(ctx, req.(*ListMatchesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MatchService_RecordResult_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RecordResultRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatchServiceServer).RecordResult(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MatchService_RecordResult_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatchServiceServer).RecordResult(ctx, req.(*RecordResultRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MatchService_RerollComponent_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RerollComponentRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MatchServiceServer).RerollComponent(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MatchService_RerollComponent_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MatchServiceServer).RerollComponent(ctx, req.(*RerollComponentRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// MatchService_ServiceDesc is the grpc.ServiceDesc for MatchService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var MatchService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "match.MatchService",
+	HandlerType: (*MatchServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GenerateMatch",
+			Handler:    _MatchService_GenerateMatch_Handler,
+		},
+		{
+			MethodName: "GetMatch",
+			Handler:    _MatchService_GetMatch_Handler,
+		},
+		{
+			MethodName: "ListMatches",
+			Handler:    _MatchService_ListMatches_Handler,
+		},
+		{
+			MethodName: "RecordResult",
+			Handler:    _MatchService_RecordResult_Handler,
+		},
+		{
+			MethodName: "RerollComponent",
+			Handler:    _MatchService_RerollComponent_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "match.proto",
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+
+	"LegacyRoot/matchpb"
+
+	"github.com/labstack/echo"
+)
+
+type PairingMode int32
+
+const (
+	// Bad pairings are generated like any other.
+	AllowPairings PairingMode = iota
+	// Bad pairings are still possible but a lot less likely.
+	DownWeightPairings
+	// Bad pairings are never generated.
+	ForbidPairings
+)
+
+// Weight kept by a bot when it makes a bad pairing with one of the players.
+const badPairingWeight = 0.25
+
+// loadMatchups reads a matchup matrix keyed by faction type names, e.g.
+// {"MARQUISE": {"ALLIANCE": 0.5}}, replacing the built in scores.
+func loadMatchups(filename string) (map[int32]map[int32]float64, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read matchups file: %w", err)
+	}
+
+	raw := map[string]map[string]float64{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to deserialize matchups: %w", err)
+	}
+
+	matchups := map[int32]map[int32]float64{}
+	for player, bots := range raw {
+		p, ok := matchpb.FactionType_value[player]
+		if !ok {
+			return nil, fmt.Errorf("unknown faction %q in matchups", player)
+		}
+		matchups[p] = map[int32]float64{}
+		for bot, score := range bots {
+			b, ok := matchpb.FactionType_value[bot]
+			if !ok {
+				return nil, fmt.Errorf("unknown faction %q in matchups", bot)
+			}
+			matchups[p][b] = score
+		}
+	}
+	return matchups, nil
+}
+
+// isBadPairing reports whether the faction's matchup score against one of the
+// players reaches cfg's threshold, the default one when cfg has none set.
+func isBadPairing(players []*matchpb.Faction, faction int32, cfg *MatchCfg) bool {
+	threshold := cfg.BadPairing
+	if threshold <= 0 {
+		threshold = defaultMatchCfg().BadPairing
+	}
+	for _, player := range players {
+		if MatchupScores[int32(player.GetType())][faction] >= threshold {
+			return true
+		}
+	}
+	return false
+}
+
+// pairingWeight scales the weight of a bot faction against the players
+// already at the table.
+func pairingWeight(players []*matchpb.Faction, faction int32, cfg *MatchCfg) float64 {
+	if cfg.Pairings == AllowPairings || !isBadPairing(players, faction, cfg) {
+		return 1
+	}
+	if cfg.Pairings == ForbidPairings {
+		return 0
+	}
+	return badPairingWeight
+}
+
+// pairingCounts counts how often each player faction faced each bot faction.
+func pairingCounts(records []*matchpb.MatchRecord) map[string]map[string]int {
+	counts := map[string]map[string]int{}
+	for _, record := range records {
+		for _, player := range record.GetMatch().GetPlayers() {
+			p := player.GetType().String()
+			if counts[p] == nil {
+				counts[p] = map[string]int{}
+			}
+			for _, bot := range record.GetMatch().GetBots() {
+				counts[p][bot.GetType().String()]++
+			}
+		}
+	}
+	return counts
+}
+
+type matchupReport struct {
+	Matrix   map[string]map[string]float64 `json:"matrix"`
+	Pairings map[string]map[string]int     `json:"pairings"`
+}
+
+func matchupsHandler(history *History) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		report := matchupReport{
+			Matrix:   map[string]map[string]float64{},
+			Pairings: pairingCounts(history.Records()),
+		}
+		for player, bots := range MatchupScores {
+			p := matchpb.FactionType(player).String()
+			report.Matrix[p] = map[string]float64{}
+			for bot, score := range bots {
+				report.Matrix[p][matchpb.FactionType(bot).String()] = score
+			}
+		}
+		return c.JSON(http.StatusOK, report)
+	}
+}
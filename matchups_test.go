@@ -0,0 +1,72 @@
+package main
+
+import (
+	"LegacyRoot/matchpb"
+	"maps"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMatchups(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "matchups.json")
+	err := os.WriteFile(filename, []byte(`{"MARQUISE": {"ALLIANCE": 1.5, "CORVID": -0.5}}`), 0o644)
+	assert.NoError(t, err)
+
+	matchups, err := loadMatchups(filename)
+	assert.NoError(t, err)
+	assert.Equal(t, map[int32]map[int32]float64{Marquise: {Alliance: 1.5, Corvid: -0.5}}, matchups)
+
+	err = os.WriteFile(filename, []byte(`{"MARQUISE": {"BADGERS": 1}}`), 0o644)
+	assert.NoError(t, err)
+	_, err = loadMatchups(filename)
+	assert.Error(t, err)
+}
+
+func TestForbidPairings(t *testing.T) {
+	players := []*matchpb.Faction{{Type: matchpb.FactionType_MARQUISE}}
+	cfg := &MatchCfg{BotEnemies: 2, Pairings: ForbidPairings, BadPairing: 0.25}
+	for range 20 {
+		bots, err := pickBotFactions(rng, &matchpb.Match{}, cfg, maps.Clone(BotCatalog), players)
+		require.NoError(t, err)
+		for _, bot := range bots {
+			assert.Less(t, MatchupScores[Marquise][int32(bot.GetType())], 0.25)
+		}
+	}
+
+	// Without a threshold set the default one applies, not every pairing
+	// being bad.
+	cfg.BadPairing = 0
+	for range 20 {
+		bots, err := pickBotFactions(rng, &matchpb.Match{}, cfg, maps.Clone(BotCatalog), players)
+		require.NoError(t, err)
+		for _, bot := range bots {
+			assert.Less(t, MatchupScores[Marquise][int32(bot.GetType())], defaultMatchCfg().BadPairing)
+		}
+	}
+
+	// Asking for more bots than are left is an error, not fewer bots.
+	cfg.BotEnemies = int32(len(BotCatalog))
+	_, err := pickBotFactions(rng, &matchpb.Match{}, cfg, maps.Clone(BotCatalog), players)
+	assert.Error(t, err)
+}
+
+func TestPairingCounts(t *testing.T) {
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.jsonl"))
+	assert.NoError(t, err)
+	for range 2 {
+		_, err := history.Add(&matchpb.Match{
+			Players: []*matchpb.Faction{{Type: matchpb.FactionType_EYRIE}},
+			Bots:    []*matchpb.Bot{{Type: matchpb.FactionType_CORVID}, {Type: matchpb.FactionType_LIZARD}},
+		})
+		assert.NoError(t, err)
+	}
+
+	// Records survive reopening the history.
+	history, err = openHistory(history.path)
+	assert.NoError(t, err)
+	assert.Equal(t, map[string]map[string]int{"EYRIE": {"CORVID": 2, "LIZARD": 2}}, pairingCounts(history.Records()))
+}
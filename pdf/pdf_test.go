@@ -0,0 +1,44 @@
+package pdf
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteTo(t *testing.T) {
+	page := New()
+	page.Text(40, 60, 18, true, "Setup (Autumn)")
+	page.Line(40, 70, 555, 70)
+	page.Box(40, 80, 10, 10)
+
+	var buf bytes.Buffer
+	_, err := page.WriteTo(&buf)
+	require.NoError(t, err)
+	doc := buf.Bytes()
+	assert.True(t, bytes.HasPrefix(doc, []byte("%PDF-1.4\n")))
+	assert.True(t, bytes.HasSuffix(doc, []byte("%%EOF\n")))
+	assert.Contains(t, buf.String(), `(Setup \(Autumn\)) Tj`)
+
+	// Every xref entry points at the object it lists.
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindSubmatch(doc)
+	require.NotNil(t, startxref)
+	xref, err := strconv.Atoi(string(startxref[1]))
+	require.NoError(t, err)
+	entries := regexp.MustCompile(`(\d{10}) 00000 n `).FindAllSubmatch(doc[xref:], -1)
+	require.Len(t, entries, 6)
+	for i, entry := range entries {
+		offset, err := strconv.Atoi(string(entry[1]))
+		require.NoError(t, err)
+		assert.True(t, bytes.HasPrefix(doc[offset:], []byte(fmt.Sprintf("%d 0 obj", i+1))))
+	}
+}
+
+func TestEscape(t *testing.T) {
+	assert.Equal(t, `a\\b \(c\) caf\351 ?`, escape("a\\b (c) café 🦊"))
+}
@@ -0,0 +1,154 @@
+// Package render formats matches as text to share in a chat.
+package render
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"LegacyRoot/matchpb"
+)
+
+const (
+	Markdown = "markdown"
+	Discord  = "discord"
+	ASCII    = "ascii"
+)
+
+// Formats lists the format names Match accepts.
+var Formats = []string{Markdown, Discord, ASCII}
+
+var DifficultyNames = map[matchpb.BotDifficulty]string{
+	matchpb.BotDifficulty_EASY:        "Easy",
+	matchpb.BotDifficulty_DEFAULT:     "Default",
+	matchpb.BotDifficulty_CHALLENGING: "Challenging",
+	matchpb.BotDifficulty_NIGHTMARE:   "Nightmare",
+}
+
+// DeckNames leaves out the unknown deck of older matches.
+var DeckNames = map[matchpb.Deck]string{
+	matchpb.Deck_BASE_DECK:            "Base",
+	matchpb.Deck_EXILES_AND_PARTISANS: "Exiles & Partisans",
+}
+
+var FactionEmoji = map[matchpb.FactionType]string{
+	matchpb.FactionType_MARQUISE:    "🐱",
+	matchpb.FactionType_EYRIE:       "🦅",
+	matchpb.FactionType_ALLIANCE:    "🐰",
+	matchpb.FactionType_VAGABOND:    "🦝",
+	matchpb.FactionType_RIVERFOLK:   "🦦",
+	matchpb.FactionType_LIZARD:      "🦎",
+	matchpb.FactionType_UNDERGROUND: "⛏️",
+	matchpb.FactionType_CORVID:      "🐦",
+	matchpb.FactionType_HUNDREDS:    "🐀",
+	matchpb.FactionType_KEEPERS:     "🦡",
+	matchpb.FactionType_BANDITS:     "🗡️",
+	matchpb.FactionType_PROTECTOR:   "🛡️",
+	matchpb.FactionType_BAND:        "🎻",
+}
+
+func BotLabel(b *matchpb.Bot) string {
+	label := fmt.Sprintf("%s (%s)", b.GetName(), DifficultyNames[b.GetDifficulty()])
+	if len(b.GetTraits()) > 0 {
+		label += ": " + strings.Join(b.GetTraits(), ", ")
+	}
+	return label
+}
+
+func HirelingLabel(h *matchpb.Hireling) string {
+	return fmt.Sprintf("at %d VP: %s (%s)", h.GetThreshold(), h.GetName(), strings.ToLower(h.GetStatus().String()))
+}
+
+// style is how a format lays out the title, section headings and list items.
+// The faction is unset for items that don't belong to one.
+type style struct {
+	title   func(string) string
+	section func(string) string
+	item    func(faction *matchpb.FactionType, label string) string
+}
+
+var styles = map[string]style{
+	Markdown: {
+		title:   func(s string) string { return "## " + s },
+		section: func(s string) string { return "**" + s + "**" },
+		item:    func(_ *matchpb.FactionType, label string) string { return "- " + label },
+	},
+	Discord: {
+		title:   func(s string) string { return "🌲 **" + s + "** 🌲" },
+		section: func(s string) string { return "__" + s + "__" },
+		item: func(faction *matchpb.FactionType, label string) string {
+			if faction == nil {
+				return "▫️ " + label
+			}
+			if emoji, ok := FactionEmoji[*faction]; ok {
+				return emoji + " " + label
+			}
+			return "🌲 " + label
+		},
+	},
+	ASCII: {
+		title:   func(s string) string { return s + "\n" + strings.Repeat("=", len(s)) },
+		section: func(s string) string { return s + ":" },
+		item:    func(_ *matchpb.FactionType, label string) string { return "  * " + label },
+	},
+}
+
+// Match writes the match in the named format. Sections the match doesn't use
+// are left out.
+func Match(w io.Writer, format string, match *matchpb.Match) error {
+	st, ok := styles[format]
+	if !ok {
+		return fmt.Errorf("unknown format %q, expected one of %s", format, strings.Join(Formats, ", "))
+	}
+
+	lines := []string{st.title(fmt.Sprintf("Root setup, %d seats", len(match.GetPlayers())+len(match.GetBots())))}
+	section := func(name string, items []string) {
+		if len(items) > 0 {
+			lines = append(lines, "", st.section(name))
+			lines = append(lines, items...)
+		}
+	}
+
+	items := []string{}
+	for _, p := range match.GetPlayers() {
+		items = append(items, st.item(p.Type.Enum(), p.GetName()))
+	}
+	section("Players", items)
+
+	items = []string{}
+	for _, b := range match.GetBots() {
+		items = append(items, st.item(b.Type.Enum(), BotLabel(b)))
+	}
+	section("Bots", items)
+
+	items = []string{}
+	for _, h := range match.GetHirelings() {
+		items = append(items, st.item(h.Type.Enum(), HirelingLabel(h)))
+	}
+	section("Hirelings", items)
+
+	if match.GetMap() != nil {
+		section("Map", []string{st.item(nil, match.GetMap().GetName())})
+	}
+	if deck, ok := DeckNames[match.GetDeck()]; ok {
+		section("Deck", []string{st.item(nil, deck)})
+	}
+
+	items = []string{}
+	for _, l := range match.GetLandmarks() {
+		items = append(items, st.item(nil, l.GetName()))
+	}
+	section("Landmarks", items)
+
+	_, err := io.WriteString(w, strings.Join(lines, "\n")+"\n")
+	return err
+}
+
+// String returns the match in the named format.
+func String(format string, match *matchpb.Match) (string, error) {
+	var sb strings.Builder
+	if err := Match(&sb, format, match); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
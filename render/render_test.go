@@ -0,0 +1,108 @@
+package render
+
+import (
+	"testing"
+
+	"LegacyRoot/matchpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMatch() *matchpb.Match {
+	return &matchpb.Match{
+		Players: []*matchpb.Faction{{Type: matchpb.FactionType_MARQUISE, Name: "Marquise de Cat"}},
+		Bots: []*matchpb.Bot{{
+			Type:       matchpb.FactionType_EYRIE,
+			Name:       "Electric Eyrie",
+			Difficulty: matchpb.BotDifficulty_CHALLENGING,
+			Traits:     []string{"Wrathful"},
+		}},
+		Hirelings: []*matchpb.Hireling{{
+			Type:      matchpb.FactionType_ALLIANCE,
+			Name:      "Rabbit Scouts",
+			Threshold: 4,
+			Status:    matchpb.HirelingStatus_DEMOTED,
+		}},
+		Map:  &matchpb.MapVal{Type: matchpb.MapType_WINTER, Name: "Winter"},
+		Deck: matchpb.Deck_EXILES_AND_PARTISANS,
+	}
+}
+
+func TestHirelingLabel(t *testing.T) {
+	assert.Equal(t, "at 4 VP: Rabbit Scouts (demoted)", HirelingLabel(testMatch().GetHirelings()[0]))
+}
+
+func TestMatchFormats(t *testing.T) {
+	for format, want := range map[string]string{
+		Markdown: `## Root setup, 2 seats
+
+**Players**
+- Marquise de Cat
+
+**Bots**
+- Electric Eyrie (Challenging): Wrathful
+
+**Hirelings**
+- at 4 VP: Rabbit Scouts (demoted)
+
+**Map**
+- Winter
+
+**Deck**
+- Exiles & Partisans
+`,
+		Discord: `🌲 **Root setup, 2 seats** 🌲
+
+__Players__
+🐱 Marquise de Cat
+
+__Bots__
+🦅 Electric Eyrie (Challenging): Wrathful
+
+__Hirelings__
+🐰 at 4 VP: Rabbit Scouts (demoted)
+
+__Map__
+▫️ Winter
+
+__Deck__
+▫️ Exiles & Partisans
+`,
+		ASCII: `Root setup, 2 seats
+===================
+
+Players:
+  * Marquise de Cat
+
+Bots:
+  * Electric Eyrie (Challenging): Wrathful
+
+Hirelings:
+  * at 4 VP: Rabbit Scouts (demoted)
+
+Map:
+  * Winter
+
+Deck:
+  * Exiles & Partisans
+`,
+	} {
+		got, err := String(format, testMatch())
+		require.NoError(t, err)
+		assert.Equal(t, want, got, format)
+	}
+}
+
+func TestMatchUnknownDeck(t *testing.T) {
+	match := testMatch()
+	match.Deck = matchpb.Deck_UNKNOWN_DECK
+	got, err := String(ASCII, match)
+	require.NoError(t, err)
+	assert.NotContains(t, got, "Deck")
+}
+
+func TestMatchUnknownFormat(t *testing.T) {
+	_, err := String("html", testMatch())
+	assert.ErrorContains(t, err, `unknown format "html"`)
+}
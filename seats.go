@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"slices"
+
+	"LegacyRoot/board"
+	"LegacyRoot/matchpb"
+	"LegacyRoot/render"
+)
+
+type SetupOrder int32
+
+const (
+	// TurnOrderSetup sets up from the first player on, clockwise.
+	TurnOrderSetup SetupOrder = iota
+	// FactionPrioritySetup follows the official rules, setting up in the
+	// order of the factions' setup letters.
+	FactionPrioritySetup
+)
+
+// tableSeats returns a seat for every player and bot of the match, players
+// first, in the order the match lists them.
+func tableSeats(match *matchpb.Match) []*matchpb.Seat {
+	seats := []*matchpb.Seat{}
+	for _, p := range match.GetPlayers() {
+		seats = append(seats, &matchpb.Seat{Faction: p.GetType()})
+	}
+	for _, b := range match.GetBots() {
+		seats = append(seats, &matchpb.Seat{Faction: b.GetType(), Bot: true})
+	}
+	return seats
+}
+
+// pickSeats seats the match's players and bots around the table at random and
+// picks who goes first.
+func pickSeats(rng *rand.Rand, match *matchpb.Match, order SetupOrder) {
+	seats := tableSeats(match)
+	rng.Shuffle(len(seats), func(i, j int) { seats[i], seats[j] = seats[j], seats[i] })
+	match.Seats = seats
+	match.FirstSeat = 0
+	if len(seats) > 0 {
+		match.FirstSeat = int32(rng.Intn(len(seats)) + 1)
+	}
+	setSetupOrder(match, order)
+}
+
+// seatInOrder seats the match's players and bots in the order the match lists
+// them, seat 1 going first. Drafted matches already have their players in
+// seat order.
+func seatInOrder(match *matchpb.Match, order SetupOrder) {
+	match.Seats = tableSeats(match)
+	match.FirstSeat = 0
+	if len(match.Seats) > 0 {
+		match.FirstSeat = 1
+	}
+	setSetupOrder(match, order)
+}
+
+func setSetupOrder(match *matchpb.Match, order SetupOrder) {
+	seats := match.GetSeats()
+	setup := []int{}
+	for i := range seats {
+		setup = append(setup, i)
+	}
+	switch order {
+	case FactionPrioritySetup:
+		slices.SortStableFunc(setup, func(a, b int) int {
+			return board.CompareSetupPriority(seats[a].GetFaction(), seats[b].GetFaction())
+		})
+	default:
+		first := int(match.GetFirstSeat()) - 1
+		for i := range setup {
+			setup[i] = (first + i) % len(seats)
+		}
+	}
+	for i, seat := range setup {
+		seats[seat].SetupOrder = int32(i + 1)
+	}
+}
+
+// seatLabel names whoever sits in seat, saying which seats are bots.
+func seatLabel(match *matchpb.Match, seat *matchpb.Seat) string {
+	if seat.GetBot() {
+		for _, b := range match.GetBots() {
+			if b.GetType() == seat.GetFaction() {
+				return render.BotLabel(b) + ", bot"
+			}
+		}
+	}
+	return getFactionName(int32(seat.GetFaction()))
+}
+
+// seatLines describes the match's seats in order along with where they
+// start, empty for matches generated before seats were.
+func seatLines(match *matchpb.Match) []string {
+	homes := map[matchpb.FactionType]int32{}
+	for _, h := range match.GetHomes() {
+		homes[h.GetFaction()] = h.GetClearing()
+	}
+	lines := []string{}
+	for i, seat := range match.GetSeats() {
+		line := fmt.Sprintf("Seat %d: %s", i+1, seatLabel(match, seat))
+		if int32(i+1) == match.GetFirstSeat() {
+			line += ", first player"
+		}
+		line += ", sets up " + ordinal(seat.GetSetupOrder())
+		if home, ok := homes[seat.GetFaction()]; ok {
+			line += fmt.Sprintf(" in clearing %d", home)
+		}
+		lines = append(lines, line)
+	}
+	return lines
+}
+
+func ordinal(n int32) string {
+	suffix := "th"
+	switch {
+	case n%100 >= 11 && n%100 <= 13:
+	case n%10 == 1:
+		suffix = "st"
+	case n%10 == 2:
+		suffix = "nd"
+	case n%10 == 3:
+		suffix = "rd"
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
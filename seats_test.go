@@ -0,0 +1,128 @@
+package main
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"LegacyRoot/board"
+	"LegacyRoot/matchpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func seatOrder(match *matchpb.Match) []matchpb.FactionType {
+	seats := slices.Clone(match.GetSeats())
+	slices.SortFunc(seats, func(a, b *matchpb.Seat) int { return int(a.GetSetupOrder() - b.GetSetupOrder()) })
+	factions := []matchpb.FactionType{}
+	for _, seat := range seats {
+		factions = append(factions, seat.GetFaction())
+	}
+	return factions
+}
+
+func TestPickSeats(t *testing.T) {
+	rng := rand.New(rand.NewSource(5))
+	cfg := defaultMatchCfg()
+	cfg.Players, cfg.BotEnemies = 1, 3
+	for range 50 {
+		match, _, err := generateMatch(&matchpb.Match{}, &cfg, rng.Int63())
+		require.NoError(t, err)
+		require.Len(t, match.GetSeats(), 1+len(match.GetBots()))
+		assert.Empty(t, Validate(match))
+		assert.True(t, proto.Equal(&matchpb.Match{Homes: board.Homes(match)}, &matchpb.Match{Homes: match.GetHomes()}))
+
+		// The first player sets up first, then the table goes clockwise.
+		first := match.GetFirstSeat()
+		for i, seat := range match.GetSeats() {
+			want := (int32(i)+1-first+int32(len(match.GetSeats())))%int32(len(match.GetSeats())) + 1
+			assert.Equal(t, want, seat.GetSetupOrder())
+		}
+
+		setSetupOrder(match, FactionPrioritySetup)
+		assert.True(t, slices.IsSortedFunc(seatOrder(match), board.CompareSetupPriority), "%v", match.GetSeats())
+		assert.Empty(t, Validate(match))
+	}
+}
+
+func TestRerollSeats(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	cfg := defaultMatchCfg()
+	cfg.BotEnemies = 3
+	match, err := generateNewMatch(rng, &matchpb.Match{}, maps.Clone(FactionNames), maps.Clone(BotCatalog), maps.Clone(Hirelings), &cfg)
+	require.NoError(t, err)
+	for _, component := range []matchpb.Component{matchpb.Component_SEATS, matchpb.Component_PLAYERS, matchpb.Component_BOTS} {
+		rerolled, err := rerollComponent(rng, match, component, &cfg)
+		require.NoError(t, err)
+		assert.Empty(t, Validate(rerolled), component)
+	}
+}
+
+func TestAdsetSeats(t *testing.T) {
+	cfg := adsetCfg(3)
+	cfg.SetupOrder = FactionPrioritySetup
+	match, _, err := generateMatch(&matchpb.Match{}, cfg, 4)
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), match.GetFirstSeat())
+	for i, seat := range match.GetSeats() {
+		assert.Equal(t, match.GetPlayers()[i].GetType(), seat.GetFaction())
+	}
+	assert.True(t, slices.IsSortedFunc(seatOrder(match), board.CompareSetupPriority))
+}
+
+func TestFactionPrioritySetup(t *testing.T) {
+	match := &matchpb.Match{FirstSeat: 1}
+	for _, f := range []matchpb.FactionType{
+		matchpb.FactionType_LIZARD, matchpb.FactionType_EYRIE, matchpb.FactionType_ALLIANCE,
+		matchpb.FactionType_HUNDREDS, matchpb.FactionType_MARQUISE, matchpb.FactionType_VAGABOND,
+	} {
+		match.Seats = append(match.Seats, &matchpb.Seat{Faction: f})
+	}
+	setSetupOrder(match, FactionPrioritySetup)
+	assert.Equal(t, []matchpb.FactionType{
+		matchpb.FactionType_MARQUISE, matchpb.FactionType_HUNDREDS, matchpb.FactionType_EYRIE,
+		matchpb.FactionType_VAGABOND, matchpb.FactionType_ALLIANCE, matchpb.FactionType_LIZARD,
+	}, seatOrder(match))
+}
+
+func TestValidateSeats(t *testing.T) {
+	match := sheetRecordFixture().GetMatch()
+	seatInOrder(match, TurnOrderSetup)
+	assert.Empty(t, Validate(match))
+
+	match.Seats[2].Bot = false
+	match.Seats[1].SetupOrder = 1
+	match.FirstSeat = 4
+	assert.Equal(t, []string{RuleSeats, RuleSeats, RuleSeats}, rules(Validate(match)))
+}
+
+func TestValidateHomes(t *testing.T) {
+	match := sheetRecordFixture().GetMatch()
+	match.Homes = []*matchpb.Home{
+		{Faction: matchpb.FactionType_MARQUISE, Clearing: 1},
+		{Faction: matchpb.FactionType_EYRIE, Clearing: 1},
+		{Faction: matchpb.FactionType_LIZARD, Clearing: 13},
+	}
+	assert.Equal(t, []string{RuleHomes, RuleHomes, RuleHomes}, rules(Validate(match)))
+}
+
+func TestSeatLines(t *testing.T) {
+	match := sheetRecordFixture().GetMatch()
+	match.Seats = []*matchpb.Seat{
+		{Faction: matchpb.FactionType_EYRIE, SetupOrder: 2},
+		{Faction: matchpb.FactionType_MARQUISE, Bot: true, SetupOrder: 1},
+		{Faction: matchpb.FactionType_ALLIANCE, SetupOrder: 3},
+	}
+	match.FirstSeat = 3
+	match.Homes = board.Homes(match)
+	assert.Equal(t, []string{
+		"Seat 1: Eyrie Dynasties, sets up 2nd in clearing 2",
+		"Seat 2: Mechanical Marquise 2.0 (Default), bot, sets up 1st in clearing 1",
+		"Seat 3: Woodland Alliance, first player, sets up 3rd",
+	}, seatLines(match))
+	assert.Equal(t, "12th", ordinal(12))
+	assert.Equal(t, "23rd", ordinal(23))
+}
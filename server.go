@@ -0,0 +1,67 @@
+package main
+
+import (
+	"github.com/a-h/templ"
+	"github.com/labstack/echo"
+)
+
+func newServer(history *History, campaigns *Campaigns) *echo.Echo {
+	e := echo.New()
+	sessions := newSessions(history, realClock{})
+	e.GET("/", generatorHandler(history))
+	e.POST("/match", generateHandler(history))
+	e.POST("/matches/:id/reroll/:component", rerollHandler(history))
+	e.GET("/history", historyHandler(history))
+	e.POST("/sessions", startSessionHandler(sessions))
+	e.GET("/s/:id", sessionPageHandler(sessions))
+	e.POST("/s/:id/join", joinPageHandler(sessions))
+	e.POST("/api/sessions", createSessionHandler(sessions))
+	e.GET("/api/sessions/:id/events", sessionEventsHandler(sessions))
+	e.POST("/api/sessions/:id/voters", joinHandler(sessions))
+	e.POST("/api/sessions/:id/generate", sessionGenerateHandler(sessions))
+	e.POST("/api/sessions/:id/reroll/:component", sessionRerollHandler(sessions))
+	e.POST("/api/sessions/:id/votes/:component", openVoteHandler(sessions))
+	e.POST("/api/sessions/:id/votes/:component/ballot", ballotHandler(sessions))
+	e.POST("/api/sessions/:id/draft", startDraftHandler(sessions))
+	e.POST("/api/sessions/:id/draft/pick", pickHandler(sessions))
+	e.POST("/api/sessions/:id/draft/undo", undoPickHandler(sessions))
+	e.GET("/api/matchups", matchupsHandler(history))
+	e.GET("/coverage", coveragePageHandler(history))
+	e.GET("/api/coverage", coverageHandler(history))
+	e.POST("/api/matches/validate", validateHandler)
+	e.GET("/api/matches/:id/text", textHandler(history))
+	e.GET("/api/matches/:id/board.svg", boardHandler(history))
+	e.GET("/api/matches/:id/sheet.pdf", sheetPDFHandler(history))
+	e.GET("/matches/:id/sheet", sheetHandler(history))
+	e.GET("/m/:id", permalinkHandler(history))
+	e.GET("/api/matches/export.csv", exportHandler(history, ',', "text/csv; charset=utf-8"))
+	e.GET("/api/matches/export.tsv", exportHandler(history, '\t', "text/tab-separated-values; charset=utf-8"))
+	e.GET("/campaigns", campaignsPageHandler(campaigns))
+	e.POST("/campaigns", createCampaignHandler(campaigns, history))
+	e.GET("/campaigns/:id", campaignPageHandler(campaigns, history))
+	e.POST("/campaigns/:id/seasons", scheduleSeasonHandler(campaigns, history))
+	e.POST("/campaigns/:id/games/:game/generate", generateGameHandler(campaigns, history))
+	e.POST("/campaigns/:id/games/:game/results", gameResultsHandler(campaigns, history))
+	e.POST("/api/campaigns", createCampaignHandler(campaigns, history))
+	e.GET("/api/campaigns/:id", campaignHandler(campaigns, history))
+	e.POST("/api/campaigns/:id/seasons", scheduleSeasonHandler(campaigns, history))
+	e.POST("/api/campaigns/:id/games/:game/generate", generateGameHandler(campaigns, history))
+	e.POST("/api/campaigns/:id/games/:game/results", gameResultsHandler(campaigns, history))
+	return e
+}
+
+// serve runs the web server and the gRPC MatchService until either stops.
+func serve(history *History, campaigns *Campaigns, addr, grpcAddr string) error {
+	errs := make(chan error, 2)
+	go func() {
+		errs <- serveGRPC(grpcAddr, history)
+	}()
+	go func() {
+		errs <- newServer(history, campaigns).Start(addr)
+	}()
+	return <-errs
+}
+
+func renderComponent(ctx echo.Context, cmp templ.Component) error {
+	return cmp.Render(ctx.Request().Context(), ctx.Response())
+}
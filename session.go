@@ -0,0 +1,486 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"LegacyRoot/draft"
+	"LegacyRoot/matchpb"
+
+	"github.com/labstack/echo"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	// How long clients wait before reconnecting to a dropped event stream.
+	sessionRetry = 2 * time.Second
+	// Comments sent on idle streams so proxies don't close them.
+	sessionHeartbeat = 15 * time.Second
+	// How long a session nobody follows is kept after its last change.
+	sessionIdle = 6 * time.Hour
+)
+
+// sessionEvent is the whole state of a session, so a client is up to date
+// after any single event.
+type sessionEvent struct {
+	Version   int             `json:"version"`
+	Record    json.RawMessage `json:"record"`
+	Votes     []VoteState     `json:"votes"`
+	Draft     *DraftState     `json:"draft"`
+	HTML      string          `json:"html"`
+	VotesHTML string          `json:"votes_html"`
+	DraftHTML string          `json:"draft_html"`
+}
+
+// Session is a table where a host generates and rerolls a match and everyone
+// else follows along.
+type Session struct {
+	Id        string
+	hostToken string
+	history   *History
+	clock     Clock
+	rules     VoteRules
+
+	mu          sync.Mutex
+	record      *matchpb.MatchRecord
+	cfg         *MatchCfg
+	votes       map[matchpb.Component]*Vote
+	voters      map[string]bool
+	draft       *draft.Draft
+	draftTimer  Timer
+	seatVoters  map[int]string
+	event       *sessionEvent
+	subscribers map[chan *sessionEvent]struct{}
+	lastActive  time.Time
+}
+
+// Sessions are kept in memory, their matches are stored in the history.
+// Sessions left idle for sessionIdle are dropped.
+type Sessions struct {
+	history *History
+	clock   Clock
+
+	mu       sync.Mutex
+	sessions map[string]*Session
+}
+
+func newSessions(history *History, clock Clock) *Sessions {
+	return &Sessions{history: history, clock: clock, sessions: map[string]*Session{}}
+}
+
+// newToken returns a random token for hosts and voters.
+func newToken() string {
+	token := make([]byte, 16)
+	rand.Read(token)
+	return hex.EncodeToString(token)
+}
+
+func (s *Sessions) Create(rules VoteRules) *Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	id := unusedMatchId(func(id string) bool { return s.sessions[id] != nil })
+	cfg := defaultMatchCfg()
+	session := &Session{
+		Id:          id,
+		hostToken:   newToken(),
+		history:     s.history,
+		clock:       s.clock,
+		rules:       rules,
+		cfg:         &cfg,
+		votes:       map[matchpb.Component]*Vote{},
+		voters:      map[string]bool{},
+		subscribers: map[chan *sessionEvent]struct{}{},
+		lastActive:  s.clock.Now(),
+	}
+	s.evict()
+	s.sessions[id] = session
+	return session
+}
+
+func (s *Sessions) Get(id string) (*Session, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.evict()
+	session, ok := s.sessions[id]
+	return session, ok
+}
+
+// evict drops the sessions nobody followed or changed for sessionIdle,
+// stopping their timers. s.mu must be held.
+func (s *Sessions) evict() {
+	now := s.clock.Now()
+	for id, session := range s.sessions {
+		if session.idle(now) {
+			session.close()
+			delete(s.sessions, id)
+		}
+	}
+}
+
+func (s *Session) idle(now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subscribers) == 0 && now.Sub(s.lastActive) >= sessionIdle
+}
+
+// close calls off the session's votes and draft along with their timers.
+func (s *Session) close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.callOff()
+}
+
+// callOff calls off the session's votes and draft. s.mu must be held.
+func (s *Session) callOff() {
+	for component, vote := range s.votes {
+		vote.stop()
+		delete(s.votes, component)
+	}
+	s.stopDraft()
+}
+
+func (s *Session) IsHost(token string) bool {
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.hostToken)) == 1
+}
+
+// Join registers a new voter with the session and returns their token.
+func (s *Session) Join() string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	token := newToken()
+	s.voters[token] = true
+	s.lastActive = s.clock.Now()
+	return token
+}
+
+// IsVoter reports whether token was handed out by Join.
+func (s *Session) IsVoter(token string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.voters[token]
+}
+
+// Record returns the session's current match, nil before the host generated
+// one.
+func (s *Session) Record() *matchpb.MatchRecord {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.record
+}
+
+// Cfg returns a copy of the config the session's match was last generated
+// or rerolled with, the default one before the first match.
+func (s *Session) Cfg() *MatchCfg {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cfg := *s.cfg
+	return &cfg
+}
+
+// Publish makes record the session's match, generated from cfg, and sends it
+// to every client. Votes and the draft on the previous match are called off.
+func (s *Session) Publish(ctx context.Context, record *matchpb.MatchRecord, cfg *MatchCfg) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.callOff()
+	s.record, s.cfg = record, cfg
+	return s.broadcast(ctx)
+}
+
+// broadcast sends the session's state to every client. s.mu must be held.
+func (s *Session) broadcast(ctx context.Context) error {
+	s.lastActive = s.clock.Now()
+	data, err := protojson.Marshal(s.record)
+	if err != nil {
+		return fmt.Errorf("failed to serialize match: %w", err)
+	}
+	votes, drafting := s.voteStates(), s.draftState()
+	var html, votesHTML, draftHTML bytes.Buffer
+	if err := matchView(s.record, "").Render(ctx, &html); err != nil {
+		return err
+	}
+	if err := votePanel(s.Id, votes).Render(ctx, &votesHTML); err != nil {
+		return err
+	}
+	if err := draftPanel(s.Id, drafting).Render(ctx, &draftHTML); err != nil {
+		return err
+	}
+
+	version := 1
+	if s.event != nil {
+		version = s.event.Version + 1
+	}
+	s.event = &sessionEvent{
+		Version:   version,
+		Record:    data,
+		Votes:     votes,
+		Draft:     drafting,
+		HTML:      html.String(),
+		VotesHTML: votesHTML.String(),
+		DraftHTML: draftHTML.String(),
+	}
+	for ch := range s.subscribers {
+		// Every event carries the whole state, so a slow client only needs
+		// the latest one.
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- s.event
+	}
+	return nil
+}
+
+// Reroll rerolls one component of the session's match and sends the result
+// to every client. A nil cfg rerolls with the config the match was generated
+// with.
+func (s *Session) Reroll(ctx context.Context, component matchpb.Component, cfg *MatchCfg) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err := s.reroll(component, cfg); err != nil {
+		return err
+	}
+	return s.broadcast(ctx)
+}
+
+// reroll is Reroll with s.mu held, without sending the result. An open vote
+// on the component is called off.
+func (s *Session) reroll(component matchpb.Component, cfg *MatchCfg) error {
+	if s.record == nil {
+		return echo.NewHTTPError(http.StatusConflict, "the session has no match to reroll yet")
+	}
+	record, cfg, err := rerollStored(s.history, s.record.GetId(), component, cfg)
+	if err != nil {
+		return err
+	}
+	if vote, ok := s.votes[component]; ok && vote.Outcome == VoteOpen {
+		vote.stop()
+		delete(s.votes, component)
+	}
+	s.record, s.cfg = record, cfg
+	return nil
+}
+
+// subscribe returns a channel of the session's events. It starts with the
+// current state unless the client already saw it.
+func (s *Session) subscribe(lastVersion int) chan *sessionEvent {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ch := make(chan *sessionEvent, 1)
+	if s.event != nil && s.event.Version != lastVersion {
+		ch <- s.event
+	}
+	s.subscribers[ch] = struct{}{}
+	return ch
+}
+
+func (s *Session) unsubscribe(ch chan *sessionEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.subscribers, ch)
+	s.lastActive = s.clock.Now()
+}
+
+func sessionParam(c echo.Context, sessions *Sessions) (*Session, error) {
+	session, ok := sessions.Get(c.Param("id"))
+	if !ok {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("no session with id %q", c.Param("id")))
+	}
+	return session, nil
+}
+
+func hostCookie(session *Session) string {
+	return "session-host-" + session.Id
+}
+
+// hostToken reads the host token from the X-Host-Token header, or the cookie
+// set for the browser that created the session.
+func hostToken(c echo.Context, session *Session) string {
+	if token := c.Request().Header.Get("X-Host-Token"); token != "" {
+		return token
+	}
+	if cookie, err := c.Cookie(hostCookie(session)); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+// hostSession looks up the session and checks the request comes from its host.
+func hostSession(c echo.Context, sessions *Sessions) (*Session, error) {
+	session, err := sessionParam(c, sessions)
+	if err != nil {
+		return nil, err
+	}
+	if !session.IsHost(hostToken(c, session)) {
+		return nil, echo.NewHTTPError(http.StatusForbidden, "only the host can change the session's match")
+	}
+	return session, nil
+}
+
+type joinResponse struct {
+	VoterToken string `json:"voter_token"`
+}
+
+// joinHandler hands the host a voter token for a player voting from a
+// script.
+func joinHandler(sessions *Sessions) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		session, err := hostSession(c, sessions)
+		if err != nil {
+			return err
+		}
+		return c.JSON(http.StatusCreated, joinResponse{VoterToken: session.Join()})
+	}
+}
+
+type createSessionResponse struct {
+	Id        string `json:"id"`
+	HostToken string `json:"host_token"`
+}
+
+func createSessionHandler(sessions *Sessions) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		rules, err := formVoteRules(c)
+		if err != nil {
+			return err
+		}
+		session := sessions.Create(rules)
+		return c.JSON(http.StatusCreated, createSessionResponse{Id: session.Id, HostToken: session.hostToken})
+	}
+}
+
+// startSessionHandler creates a session from the browser, making it the host.
+func startSessionHandler(sessions *Sessions) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		rules, err := formVoteRules(c)
+		if err != nil {
+			return err
+		}
+		session := sessions.Create(rules)
+		c.SetCookie(&http.Cookie{
+			Name:     hostCookie(session),
+			Value:    session.hostToken,
+			Path:     "/",
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		})
+		return c.Redirect(http.StatusSeeOther, "/s/"+session.Id)
+	}
+}
+
+func sessionPageHandler(sessions *Sessions) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		session, err := sessionParam(c, sessions)
+		if err != nil {
+			return err
+		}
+		voter := session.IsVoter(voterToken(c, session))
+		return renderComponent(c, sessionPage(session, session.IsHost(hostToken(c, session)), voter, session.Cfg(), session.Record(), session.Votes(), session.Draft()))
+	}
+}
+
+// joinPageHandler joins the browser to the session's voters from the join
+// button on the session page, which it then goes back to. Browsers already
+// voting keep their token.
+func joinPageHandler(sessions *Sessions) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		session, err := sessionParam(c, sessions)
+		if err != nil {
+			return err
+		}
+		if !session.IsVoter(voterToken(c, session)) {
+			c.SetCookie(&http.Cookie{Name: voterCookie(session), Value: session.Join(), Path: "/", HttpOnly: true, SameSite: http.SameSiteLaxMode})
+		}
+		return c.Redirect(http.StatusSeeOther, "/s/"+session.Id)
+	}
+}
+
+func sessionGenerateHandler(sessions *Sessions) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		session, err := hostSession(c, sessions)
+		if err != nil {
+			return err
+		}
+		cfg, err := formCfg(c)
+		if err != nil {
+			return err
+		}
+		record, err := generateStored(session.history, cfg)
+		if err != nil {
+			return err
+		}
+		if err := session.Publish(c.Request().Context(), record, usedCfg(cfg)); err != nil {
+			return err
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func sessionRerollHandler(sessions *Sessions) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		session, err := hostSession(c, sessions)
+		if err != nil {
+			return err
+		}
+		component, err := componentParam(c)
+		if err != nil {
+			return err
+		}
+		cfg, err := rerollFormCfg(c)
+		if err != nil {
+			return err
+		}
+		if err := session.Reroll(c.Request().Context(), component, cfg); err != nil {
+			return err
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+// sessionEventsHandler streams the session's match as Server-Sent Events. A
+// client reconnecting with the Last-Event-ID it saw only gets newer state.
+func sessionEventsHandler(sessions *Sessions) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		session, err := sessionParam(c, sessions)
+		if err != nil {
+			return err
+		}
+		lastVersion, _ := strconv.Atoi(c.Request().Header.Get("Last-Event-ID"))
+		events := session.subscribe(lastVersion)
+		defer session.unsubscribe(events)
+
+		w := c.Response()
+		w.Header().Set(echo.HeaderContentType, "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		fmt.Fprintf(w, "retry: %d\n\n", sessionRetry.Milliseconds())
+		w.Flush()
+
+		heartbeat := time.NewTicker(sessionHeartbeat)
+		defer heartbeat.Stop()
+		for {
+			select {
+			case <-c.Request().Context().Done():
+				return nil
+			case <-heartbeat.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+			case event := <-events:
+				data, err := json.Marshal(event)
+				if err != nil {
+					return err
+				}
+				fmt.Fprintf(w, "id: %d\nevent: match\ndata: %s\n\n", event.Version, data)
+			}
+			w.Flush()
+		}
+	}
+}
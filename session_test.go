@@ -0,0 +1,250 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"LegacyRoot/matchpb"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// sseClient reads the match events of a session stream.
+type sseClient struct {
+	resp   *http.Response
+	events chan sseMessage
+}
+
+type sseMessage struct {
+	id    int
+	event sessionEvent
+}
+
+func connectSession(t *testing.T, srv *httptest.Server, id string, lastEventId int) *sseClient {
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"/api/sessions/"+id+"/events", nil)
+	require.NoError(t, err)
+	if lastEventId > 0 {
+		req.Header.Set("Last-Event-ID", strconv.Itoa(lastEventId))
+	}
+	resp, err := srv.Client().Do(req)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	require.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	t.Cleanup(func() { resp.Body.Close() })
+
+	client := &sseClient{resp: resp, events: make(chan sseMessage, 10)}
+	go func() {
+		defer close(client.events)
+		scanner := bufio.NewScanner(resp.Body)
+		scanner.Buffer(nil, 1<<20)
+		msg := sseMessage{}
+		for scanner.Scan() {
+			line := scanner.Text()
+			switch {
+			case strings.HasPrefix(line, "id: "):
+				msg.id, _ = strconv.Atoi(strings.TrimPrefix(line, "id: "))
+			case strings.HasPrefix(line, "data: "):
+				json.Unmarshal([]byte(strings.TrimPrefix(line, "data: ")), &msg.event)
+			case line == "" && msg.id > 0:
+				client.events <- msg
+				msg = sseMessage{}
+			}
+		}
+	}()
+	return client
+}
+
+func (c *sseClient) next(t *testing.T) sseMessage {
+	select {
+	case msg, ok := <-c.events:
+		require.True(t, ok, "stream closed")
+		return msg
+	case <-time.After(5 * time.Second):
+		t.Fatal("no event")
+		return sseMessage{}
+	}
+}
+
+func (c *sseClient) none(t *testing.T) {
+	select {
+	case msg := <-c.events:
+		t.Fatalf("unexpected event %d", msg.id)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func sessionRecord(t *testing.T, msg sseMessage) *matchpb.MatchRecord {
+	record := &matchpb.MatchRecord{}
+	require.NoError(t, protojson.Unmarshal(msg.event.Record, record))
+	return record
+}
+
+func TestSessionBroadcast(t *testing.T) {
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.pb"))
+	require.NoError(t, err)
+	srv := httptest.NewServer(newServer(history, &Campaigns{}))
+	// Registered first, so it runs after the clients have hung up.
+	t.Cleanup(srv.Close)
+
+	resp, err := srv.Client().Post(srv.URL+"/api/sessions", "", nil)
+	require.NoError(t, err)
+	created := createSessionResponse{}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&created))
+	resp.Body.Close()
+
+	host := func(path string, form url.Values, token string) int {
+		req, err := http.NewRequest(http.MethodPost, srv.URL+"/api/sessions/"+created.Id+path, strings.NewReader(form.Encode()))
+		require.NoError(t, err)
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		req.Header.Set("X-Host-Token", token)
+		resp, err := srv.Client().Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	clients := []*sseClient{}
+	for range 3 {
+		clients = append(clients, connectSession(t, srv, created.Id, 0))
+	}
+
+	assert.Equal(t, http.StatusConflict, host("/reroll/map", nil, created.HostToken))
+	assert.Equal(t, http.StatusForbidden, host("/generate", nil, "not-the-host"))
+	require.Equal(t, http.StatusNoContent, host("/generate", url.Values{"bots": {"2"}}, created.HostToken))
+	generated := history.Records()[0]
+	for _, client := range clients {
+		msg := client.next(t)
+		assert.Equal(t, 1, msg.id)
+		assert.Equal(t, generated.GetId(), sessionRecord(t, msg).GetId())
+		assert.Len(t, sessionRecord(t, msg).GetMatch().GetBots(), 2)
+		assert.Contains(t, msg.event.HTML, `<div id="match">`)
+		assert.NotContains(t, msg.event.HTML, "hx-post")
+	}
+
+	require.Equal(t, http.StatusNoContent, host("/reroll/landmarks", nil, created.HostToken))
+	for _, client := range clients {
+		msg := client.next(t)
+		assert.Equal(t, 2, msg.id)
+		assert.Equal(t, generated.GetId(), sessionRecord(t, msg).GetId())
+	}
+
+	// A late client gets the current state, a reconnecting one only what it
+	// missed.
+	late := connectSession(t, srv, created.Id, 0)
+	assert.Equal(t, 2, late.next(t).id)
+	reconnected := connectSession(t, srv, created.Id, 2)
+	reconnected.none(t)
+	stale := connectSession(t, srv, created.Id, 1)
+	assert.Equal(t, 2, stale.next(t).id)
+
+	require.Equal(t, http.StatusNoContent, host("/generate", nil, created.HostToken))
+	for _, client := range append(clients, late, reconnected, stale) {
+		assert.Equal(t, 3, client.next(t).id)
+	}
+	assert.Len(t, history.Records(), 2)
+}
+
+func TestSessionPages(t *testing.T) {
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.pb"))
+	require.NoError(t, err)
+	e := newServer(history, &Campaigns{})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/sessions", nil))
+	require.Equal(t, http.StatusSeeOther, rec.Code)
+	page := rec.Header().Get("Location")
+	cookies := rec.Result().Cookies()
+	require.Len(t, cookies, 1)
+
+	req := httptest.NewRequest(http.MethodGet, page, nil)
+	req.AddCookie(cookies[0])
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `<form id="cfg"`)
+	// Viewing the page doesn't join the voters, the join button does.
+	assert.Empty(t, rec.Result().Cookies())
+	assert.Contains(t, rec.Body.String(), `action="`+page+`/join"`)
+
+	// Joining hands the browser a voter token, replacing made up ones.
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, page+"/join", nil))
+	require.Equal(t, http.StatusSeeOther, rec.Code)
+	assert.Equal(t, page, rec.Header().Get("Location"))
+	voter := rec.Result().Cookies()
+	require.Len(t, voter, 1)
+	assert.Equal(t, "session-voter-"+strings.TrimPrefix(page, "/s/"), voter[0].Name)
+	for _, cookie := range []*http.Cookie{voter[0], {Name: voter[0].Name, Value: "ana"}} {
+		req = httptest.NewRequest(http.MethodPost, page+"/join", nil)
+		req.AddCookie(cookie)
+		rec = httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		assert.Equal(t, cookie.Value == "ana", len(rec.Result().Cookies()) == 1, cookie.Value)
+	}
+	req = httptest.NewRequest(http.MethodGet, page, nil)
+	req.AddCookie(voter[0])
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.NotContains(t, rec.Body.String(), `action="`+page+`/join"`)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, page, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.NotContains(t, rec.Body.String(), `<form id="cfg"`)
+	assert.Contains(t, rec.Body.String(), "Waiting for the host")
+
+	// The host's options show what the session last generated with.
+	req = httptest.NewRequest(http.MethodPost, "/api/sessions"+strings.TrimPrefix(page, "/s")+"/generate", strings.NewReader("bots=3"))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+	req.AddCookie(cookies[0])
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	req = httptest.NewRequest(http.MethodGet, page, nil)
+	req.AddCookie(cookies[0])
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Contains(t, rec.Body.String(), `name="bots" min="0" max="4" value="3"`)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/s/missing", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestSessionsEvictIdle(t *testing.T) {
+	ctx := context.Background()
+	session, clock, _ := votingSession(t, defaultVoteRules(), 2)
+	sessions := &Sessions{history: session.history, clock: clock, sessions: map[string]*Session{session.Id: session}}
+	require.NoError(t, session.OpenVote(ctx, matchpb.Component_MAP))
+	vote := session.votes[matchpb.Component_MAP]
+
+	// Followers keep a session around however long it sits idle.
+	events := session.subscribe(0)
+	clock.now = clock.now.Add(sessionIdle)
+	_, ok := sessions.Get(session.Id)
+	assert.True(t, ok)
+	session.unsubscribe(events)
+
+	clock.now = clock.now.Add(sessionIdle - time.Second)
+	_, ok = sessions.Get(session.Id)
+	assert.True(t, ok)
+	clock.now = clock.now.Add(time.Second)
+	sessions.Create(defaultVoteRules())
+	_, ok = sessions.Get(session.Id)
+	assert.False(t, ok)
+	assert.True(t, vote.timer.(*fakeTimer).stopped)
+	assert.Empty(t, session.Votes())
+	assert.Len(t, sessions.sessions, 1)
+}
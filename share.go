@@ -0,0 +1,100 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"LegacyRoot/board"
+	"LegacyRoot/matchpb"
+	"LegacyRoot/render"
+
+	"github.com/labstack/echo"
+)
+
+// recordParam looks up the match named by the id path parameter.
+func recordParam(c echo.Context, history *History) (*matchpb.MatchRecord, error) {
+	record, ok := history.Get(c.Param("id"))
+	if !ok {
+		return nil, echo.NewHTTPError(http.StatusNotFound, fmt.Sprintf("no match with id %q", c.Param("id")))
+	}
+	return record, nil
+}
+
+// textHandler renders a stored match as text to paste in a chat, in the
+// format named by the format query parameter, Markdown by default.
+func textHandler(history *History) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		record, err := recordParam(c, history)
+		if err != nil {
+			return err
+		}
+		format := c.QueryParam("format")
+		if format == "" {
+			format = render.Markdown
+		}
+		text, err := render.String(format, record.GetMatch())
+		if err != nil {
+			return echo.NewHTTPError(http.StatusBadRequest, err.Error())
+		}
+		contentType := echo.MIMETextPlainCharsetUTF8
+		if format == render.Markdown {
+			contentType = "text/markdown; charset=utf-8"
+		}
+		return c.Blob(http.StatusOK, contentType, []byte(text))
+	}
+}
+
+// boardHandler draws a stored match's board setup.
+func boardHandler(history *History) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		record, err := recordParam(c, history)
+		if err != nil {
+			return err
+		}
+		var buf bytes.Buffer
+		if err := board.SVG(&buf, record.GetMatch()); err != nil {
+			return err
+		}
+		return c.Blob(http.StatusOK, "image/svg+xml", buf.Bytes())
+	}
+}
+
+// pageMeta is what a link to a page shows when it is previewed in a chat.
+type pageMeta struct {
+	Title       string
+	Description string
+	URL         string
+}
+
+func matchMeta(c echo.Context, record *matchpb.MatchRecord) pageMeta {
+	match := record.GetMatch()
+	seats := []string{}
+	for _, p := range match.GetPlayers() {
+		seats = append(seats, p.GetName())
+	}
+	for _, b := range match.GetBots() {
+		seats = append(seats, b.GetName()+" (bot)")
+	}
+	board := match.GetMap().GetName()
+	if deck, ok := render.DeckNames[match.GetDeck()]; ok {
+		board += " with the " + deck + " deck"
+	}
+	return pageMeta{
+		Title:       "Root setup " + record.GetId(),
+		Description: fmt.Sprintf("%d seats on %s: %s", len(seats), board, strings.Join(seats, ", ")),
+		URL:         c.Scheme() + "://" + c.Request().Host + c.Request().URL.Path,
+	}
+}
+
+// permalinkHandler serves the page a match is shared by.
+func permalinkHandler(history *History) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		record, err := recordParam(c, history)
+		if err != nil {
+			return err
+		}
+		return renderComponent(c, permalinkPage(matchMeta(c, record), record))
+	}
+}
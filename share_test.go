@@ -0,0 +1,96 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"LegacyRoot/matchpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTextEndpoint(t *testing.T) {
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.pb"))
+	require.NoError(t, err)
+	record, err := history.Add(&matchpb.Match{Players: []*matchpb.Faction{NewFaction(Corvid)}})
+	require.NoError(t, err)
+	e := newServer(history, &Campaigns{})
+
+	get := func(url string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		return rec
+	}
+
+	rec := get("/api/matches/" + record.GetId() + "/text")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "text/markdown")
+	assert.Contains(t, rec.Body.String(), "- Corvid Conspiracy")
+	assert.NotContains(t, rec.Body.String(), "Hirelings")
+
+	rec = get("/api/matches/" + record.GetId() + "/text?format=discord")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "🐦 Corvid Conspiracy")
+
+	assert.Equal(t, http.StatusBadRequest, get("/api/matches/"+record.GetId()+"/text?format=html").Code)
+	assert.Equal(t, http.StatusNotFound, get("/api/matches/missing/text").Code)
+}
+
+func TestBoardEndpoint(t *testing.T) {
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.pb"))
+	require.NoError(t, err)
+	e := newServer(history, &Campaigns{})
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/match", nil))
+	require.Equal(t, http.StatusSeeOther, rec.Code)
+	record := history.Records()[0]
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/m/"+record.GetId(), nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `src="/api/matches/`+record.GetId()+`/board.svg?v=`)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/matches/"+record.GetId()+"/board.svg", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "image/svg+xml", rec.Header().Get("Content-Type"))
+	assert.Contains(t, rec.Body.String(), record.GetMatch().GetMap().GetName())
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/api/matches/missing/board.svg", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestPermalink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history.pb")
+	history, err := openHistory(path)
+	require.NoError(t, err)
+
+	rec := httptest.NewRecorder()
+	newServer(history, &Campaigns{}).ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/match", nil))
+	require.Equal(t, http.StatusSeeOther, rec.Code)
+	link := rec.Header().Get("Location")
+	record := history.Records()[0]
+	assert.Equal(t, "/m/"+record.GetId(), link)
+	assert.Regexp(t, `^[a-z]+-[a-z]+-\d{2}$`, record.GetId())
+
+	// Links resolve from the stored history after a restart.
+	history, err = openHistory(path)
+	require.NoError(t, err)
+	rec = httptest.NewRecorder()
+	newServer(history, &Campaigns{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "http://root.example"+link, nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	assert.Contains(t, body, `<meta property="og:title" content="Root setup `+record.GetId()+`">`)
+	assert.Contains(t, body, `<meta property="og:url" content="http://root.example`+link+`">`)
+	assert.Contains(t, body, `<meta property="og:description" content="`)
+	assert.Contains(t, body, record.GetMatch().GetMap().GetName())
+
+	rec = httptest.NewRecorder()
+	newServer(history, &Campaigns{}).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/m/missing", nil))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"LegacyRoot/board"
+	"LegacyRoot/matchpb"
+	"LegacyRoot/pdf"
+	"LegacyRoot/render"
+
+	"github.com/labstack/echo"
+)
+
+// SetupSheet is everything the table needs to set up a match, shared by the
+// printable page and the PDF.
+type SetupSheet struct {
+	Id        string
+	Date      string
+	Map       string
+	Deck      string
+	Seats     []string
+	Hirelings []string
+	Landmarks []string
+	Steps     []string
+}
+
+func newSetupSheet(record *matchpb.MatchRecord) SetupSheet {
+	match := record.GetMatch()
+	sheet := SetupSheet{
+		Id:   record.GetId(),
+		Date: record.GetCreatedAt().AsTime().Format("2006-01-02"),
+		Map:  match.GetMap().GetName(),
+		Deck: render.DeckNames[match.GetDeck()],
+	}
+
+	names := map[matchpb.FactionType]string{}
+	for _, p := range match.GetPlayers() {
+		names[p.GetType()] = p.GetName()
+	}
+	for _, b := range match.GetBots() {
+		names[b.GetType()] = b.GetName()
+	}
+	sheet.Seats = seatLines(match)
+	if len(sheet.Seats) == 0 {
+		// Matches from before seats were sit in the order they list factions.
+		for i, seat := range tableSeats(match) {
+			sheet.Seats = append(sheet.Seats, fmt.Sprintf("Seat %d: %s", i+1, seatLabel(match, seat)))
+		}
+	}
+	for _, h := range match.GetHirelings() {
+		sheet.Hirelings = append(sheet.Hirelings, render.HirelingLabel(h))
+	}
+
+	layout := board.Layouts[match.GetMap().GetType()]
+	for _, l := range match.GetLandmarks() {
+		sheet.Landmarks = append(sheet.Landmarks, fmt.Sprintf("%s in clearing %d", l.GetName(), layout.Landmarks[l.GetType()]))
+	}
+
+	sheet.Steps = append(sheet.Steps, fmt.Sprintf("Lay out the %s map", sheet.Map))
+	if len(sheet.Landmarks) > 0 {
+		sheet.Steps = append(sheet.Steps, "Place the landmarks")
+	}
+	if len(sheet.Hirelings) > 0 {
+		sheet.Steps = append(sheet.Steps, "Place the hireling cards and their markers on the score track")
+	}
+	homes := board.StartingClearings(match)
+	for _, f := range board.FactionsInSetupOrder(match) {
+		step := "Set up " + names[f]
+		if home, ok := homes[f]; ok {
+			step += fmt.Sprintf(" in clearing %d", home)
+		}
+		sheet.Steps = append(sheet.Steps, step)
+	}
+	if sheet.Deck != "" {
+		sheet.Steps = append(sheet.Steps, fmt.Sprintf("Shuffle the %s deck and deal three cards to each player", sheet.Deck))
+	} else {
+		sheet.Steps = append(sheet.Steps, "Deal three cards to each player")
+	}
+	return sheet
+}
+
+// writePDF lays the sheet out on one A4 page.
+func (s SetupSheet) writePDF(page *pdf.Page) {
+	const left, lineHeight = 50.0, 18.0
+	y := 70.0
+	line := func(size float64, bold bool, text string) {
+		page.Text(left, y, size, bold, text)
+		y += lineHeight
+	}
+	section := func(title string, items []string) {
+		if len(items) == 0 {
+			return
+		}
+		y += 8
+		line(14, true, title)
+		for _, item := range items {
+			line(11, false, item)
+		}
+	}
+
+	line(20, true, "Root setup sheet")
+	line(10, false, fmt.Sprintf("Match %s, %s", s.Id, s.Date))
+	page.Line(left, y-8, pdf.PageWidth-left, y-8)
+	section("Seats", s.Seats)
+	section("Map", []string{s.Map})
+	if s.Deck != "" {
+		section("Deck", []string{s.Deck})
+	}
+	section("Hirelings", s.Hirelings)
+	section("Landmarks", s.Landmarks)
+
+	y += 8
+	line(14, true, "Setup order")
+	for _, step := range s.Steps {
+		page.Box(left, y-9, 9, 9)
+		page.Text(left+16, y, 11, false, step)
+		y += lineHeight
+	}
+}
+
+// sheetHandler serves the setup sheet as a page meant to be printed.
+func sheetHandler(history *History) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		record, err := recordParam(c, history)
+		if err != nil {
+			return err
+		}
+		return renderComponent(c, sheetPage(newSetupSheet(record)))
+	}
+}
+
+func sheetPDFHandler(history *History) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		record, err := recordParam(c, history)
+		if err != nil {
+			return err
+		}
+		page := pdf.New()
+		newSetupSheet(record).writePDF(page)
+		var buf bytes.Buffer
+		if _, err := page.WriteTo(&buf); err != nil {
+			return err
+		}
+		c.Response().Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("inline; filename=%q", "setup-"+record.GetId()+".pdf"))
+		return c.Blob(http.StatusOK, "application/pdf", buf.Bytes())
+	}
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"LegacyRoot/matchpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func sheetRecordFixture() *matchpb.MatchRecord {
+	return &matchpb.MatchRecord{
+		Id:        "0badcafe",
+		CreatedAt: timestamppb.New(time.Date(2024, 5, 4, 19, 0, 0, 0, time.UTC)),
+		Match: &matchpb.Match{
+			Players: []*matchpb.Faction{NewFaction(Alliance), NewFaction(Eyrie)},
+			Bots: []*matchpb.Bot{{
+				Type:       matchpb.FactionType_MARQUISE,
+				Name:       "Mechanical Marquise 2.0",
+				Difficulty: matchpb.BotDifficulty_DEFAULT,
+				Clockwork:  matchpb.BotType_MECHANICAL_MARQUISE,
+			}},
+			Hirelings: []*matchpb.Hireling{{Type: matchpb.FactionType_VAGABOND, Name: "The Exile", Threshold: 4}},
+			Map:       &matchpb.MapVal{Type: matchpb.MapType_LAKE, Name: "Lake"},
+			Landmarks: []*matchpb.Landmark{{Type: matchpb.LandmarkType_FERRY, Name: "The Ferry"}},
+			Deck:      matchpb.Deck_BASE_DECK,
+		},
+	}
+}
+
+func TestNewSetupSheet(t *testing.T) {
+	sheet := newSetupSheet(sheetRecordFixture())
+	assert.Equal(t, "2024-05-04", sheet.Date)
+	assert.Equal(t, []string{
+		"Seat 1: Woodland Alliance",
+		"Seat 2: Eyrie Dynasties",
+		"Seat 3: Mechanical Marquise 2.0 (Default), bot",
+	}, sheet.Seats)
+	assert.Equal(t, []string{"The Ferry in clearing 10"}, sheet.Landmarks)
+	assert.Equal(t, []string{
+		"Lay out the Lake map",
+		"Place the landmarks",
+		"Place the hireling cards and their markers on the score track",
+		"Set up Mechanical Marquise 2.0 in clearing 1",
+		"Set up Eyrie Dynasties in clearing 2",
+		"Set up Woodland Alliance",
+		"Shuffle the Base deck and deal three cards to each player",
+	}, sheet.Steps)
+}
+
+func TestSheetEndpoints(t *testing.T) {
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.pb"))
+	require.NoError(t, err)
+	require.NoError(t, history.Import(sheetRecordFixture()))
+	e := newServer(history, &Campaigns{})
+
+	get := func(url string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, url, nil))
+		return rec
+	}
+
+	rec := get("/matches/0badcafe/sheet")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "@media print")
+	assert.Contains(t, rec.Body.String(), "Set up Eyrie Dynasties in clearing 2")
+
+	rec = get("/api/matches/0badcafe/sheet.pdf")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "application/pdf", rec.Header().Get("Content-Type"))
+	assert.True(t, strings.HasPrefix(rec.Body.String(), "%PDF-"))
+	assert.Contains(t, rec.Body.String(), "(Seat 3: Mechanical Marquise 2.0 \\(Default\\), bot) Tj")
+
+	assert.Equal(t, http.StatusNotFound, get("/matches/missing/sheet").Code)
+	assert.Equal(t, http.StatusNotFound, get("/api/matches/missing/sheet.pdf").Code)
+}
@@ -0,0 +1,110 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// The history and campaigns are stores, files of length delimited binary
+// messages, or of one protojson message per line when the file has a .jsonl
+// extension so it can be edited by hand. name says what a store holds in
+// errors.
+
+func isJSONStore(path string) bool {
+	return filepath.Ext(path) == ".jsonl"
+}
+
+// readStore reads the messages stored at path, none before the file exists.
+// unmarshalJSON reads a line of a .jsonl store into a new message.
+func readStore[M proto.Message](path, name string, newMessage func() M, unmarshalJSON func([]byte, M) error) ([]M, error) {
+	messages := []M{}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return messages, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	if isJSONStore(path) {
+		scanner := bufio.NewScanner(bytes.NewReader(data))
+		scanner.Buffer(nil, 1<<20)
+		for scanner.Scan() {
+			if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+				continue
+			}
+			message := newMessage()
+			if err := unmarshalJSON(scanner.Bytes(), message); err != nil {
+				return nil, fmt.Errorf("failed to deserialize %s: %w", name, err)
+			}
+			messages = append(messages, message)
+		}
+		return messages, scanner.Err()
+	}
+	r := bufio.NewReader(bytes.NewReader(data))
+	for {
+		message := newMessage()
+		err := protodelim.UnmarshalFrom(r, message)
+		if errors.Is(err, io.EOF) {
+			return messages, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to deserialize %s: %w", name, err)
+		}
+		messages = append(messages, message)
+	}
+}
+
+// appendStore adds message at the end of the store at path.
+func appendStore(path, name string, message proto.Message) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", name, err)
+	}
+	defer f.Close()
+	if err := encodeMessage(f, message, isJSONStore(path)); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+// writeStore rewrites the whole store at path, replacing the file only once
+// everything is written.
+func writeStore[M proto.Message](path, name string, messages []M) error {
+	var buf bytes.Buffer
+	for _, message := range messages {
+		if err := encodeMessage(&buf, message, isJSONStore(path)); err != nil {
+			return fmt.Errorf("failed to serialize %s: %w", name, err)
+		}
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, buf.Bytes(), 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", name, err)
+	}
+	return nil
+}
+
+func encodeMessage(w io.Writer, message proto.Message, json bool) error {
+	if !json {
+		_, err := protodelim.MarshalTo(w, message)
+		return err
+	}
+	line, err := protojson.Marshal(message)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(append(line, '\n'))
+	return err
+}
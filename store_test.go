@@ -0,0 +1,42 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"LegacyRoot/matchpb"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestStore(t *testing.T) {
+	newCampaign := func() *matchpb.Campaign { return &matchpb.Campaign{} }
+	unmarshal := func(line []byte, campaign *matchpb.Campaign) error { return protojson.Unmarshal(line, campaign) }
+	for _, name := range []string{"store.pb", "store.jsonl"} {
+		path := filepath.Join(t.TempDir(), name)
+		stored, err := readStore(path, "campaigns", newCampaign, unmarshal)
+		require.NoError(t, err)
+		assert.Empty(t, stored, name)
+
+		campaigns := []*matchpb.Campaign{{Id: "a"}, {Id: "b", Roster: []string{"ana"}}}
+		require.NoError(t, writeStore(path, "campaigns", campaigns[:1]))
+		require.NoError(t, appendStore(path, "campaigns", campaigns[1]))
+		stored, err = readStore(path, "campaigns", newCampaign, unmarshal)
+		require.NoError(t, err)
+		require.Len(t, stored, 2, name)
+		for i := range campaigns {
+			assert.True(t, proto.Equal(campaigns[i], stored[i]), name)
+		}
+		_, err = os.Stat(path + ".tmp")
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	}
+
+	path := filepath.Join(t.TempDir(), "broken.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("{\"id\": \"a\"}\n\nnot json\n"), 0o644))
+	_, err := readStore(path, "campaigns", newCampaign, unmarshal)
+	assert.ErrorContains(t, err, "failed to deserialize campaigns")
+}
@@ -0,0 +1,209 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	"LegacyRoot/matchpb"
+
+	"github.com/labstack/echo"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+const (
+	RuleDuplicateFaction  = "duplicate-faction"
+	RuleHirelingInPlay    = "hireling-in-play"
+	RuleNameMismatch      = "name-mismatch"
+	RuleTooManyHirelings  = "too-many-hirelings"
+	RuleHirelingThreshold = "hireling-threshold"
+	RuleTooManyLandmarks  = "too-many-landmarks"
+	RuleDuplicateLandmark = "duplicate-landmark"
+	RuleMapNameMismatch   = "map-name-mismatch"
+	RuleClearingSuits     = "clearing-suits"
+	RuleSeats             = "seats"
+	RuleHomes             = "homes"
+	maxLandmarks          = 3
+	maxHirelings          = 3
+	clearingsPerMap       = 12
+)
+
+type Violation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+// ValidationError is returned for matches breaking any of the setup rules.
+type ValidationError []Violation
+
+func (v ValidationError) Error() string {
+	messages := []string{}
+	for _, violation := range v {
+		messages = append(messages, fmt.Sprintf("%s: %s", violation.Rule, violation.Message))
+	}
+	return "invalid match: " + strings.Join(messages, "; ")
+}
+
+// Validate checks a match against the setup rules, returning every rule it
+// breaks.
+func Validate(match *matchpb.Match) []Violation {
+	violations := []Violation{}
+	violate := func(rule, format string, args ...any) {
+		violations = append(violations, Violation{Rule: rule, Message: fmt.Sprintf(format, args...)})
+	}
+
+	inPlay := map[matchpb.FactionType]bool{}
+	for _, player := range match.GetPlayers() {
+		if inPlay[player.GetType()] {
+			violate(RuleDuplicateFaction, "%v is played more than once", player.GetType())
+		}
+		inPlay[player.GetType()] = true
+		if want := getFactionName(int32(player.GetType())); player.GetName() != want {
+			violate(RuleNameMismatch, "player %v is named %q instead of %q", player.GetType(), player.GetName(), want)
+		}
+	}
+
+	for _, bot := range match.GetBots() {
+		if inPlay[bot.GetType()] {
+			violate(RuleDuplicateFaction, "bot %q plays %v which is already in play", bot.GetName(), bot.GetType())
+		}
+		inPlay[bot.GetType()] = true
+		if entry, ok := BotCatalog[bot.GetClockwork()]; ok && entry.Mirrors != int32(bot.GetType()) {
+			violate(RuleNameMismatch, "bot %q is a %v, which doesn't play %v", bot.GetName(), bot.GetClockwork(), bot.GetType())
+		}
+		// Older matches named bots after the faction they mirror.
+		names := []string{getFactionName(int32(bot.GetType()))}
+		for clockwork, entry := range BotCatalog {
+			if entry.Mirrors != int32(bot.GetType()) {
+				continue
+			}
+			names = append(names, entry.Name)
+			if bot.GetName() == entry.Name && bot.GetClockwork() != clockwork {
+				violate(RuleNameMismatch, "bot %q is a %v instead of %v", bot.GetName(), bot.GetClockwork(), clockwork)
+			}
+		}
+		if !slices.Contains(names, bot.GetName()) {
+			violate(RuleNameMismatch, "bot %v is named %q instead of one of %q", bot.GetType(), bot.GetName(), names)
+		}
+	}
+
+	if len(match.GetHirelings()) > maxHirelings {
+		violate(RuleTooManyHirelings, "%d hirelings, at most %d can be hired", len(match.GetHirelings()), maxHirelings)
+	}
+	thresholds := map[int32]bool{}
+	for _, hireling := range match.GetHirelings() {
+		if inPlay[hireling.GetType()] {
+			violate(RuleHirelingInPlay, "hireling %q is from %v which is already in play", hireling.GetName(), hireling.GetType())
+		}
+		inPlay[hireling.GetType()] = true
+		names := Hirelings[int32(hireling.GetType())]
+		if int(hireling.GetStatus()) >= len(names) || hireling.GetName() != names[hireling.GetStatus()] {
+			violate(RuleNameMismatch, "%v hireling %v is named %q", hireling.GetType(), hireling.GetStatus(), hireling.GetName())
+		}
+		if !slices.Contains(HirelingThresholds, hireling.GetThreshold()) || thresholds[hireling.GetThreshold()] {
+			violate(RuleHirelingThreshold, "hireling %q is set up at %d VP", hireling.GetName(), hireling.GetThreshold())
+		}
+		thresholds[hireling.GetThreshold()] = true
+	}
+
+	if match.GetMap() != nil {
+		if want := MapNames[int32(match.GetMap().GetType())]; match.GetMap().GetName() != want {
+			violate(RuleMapNameMismatch, "map %v is named %q instead of %q", match.GetMap().GetType(), match.GetMap().GetName(), want)
+		}
+	}
+
+	if clearings := match.GetClearings(); len(clearings) > 0 {
+		numbers := map[int32]bool{}
+		suits := map[matchpb.Suit]int{}
+		for _, c := range clearings {
+			if c.GetNumber() < 1 || c.GetNumber() > clearingsPerMap || numbers[c.GetNumber()] {
+				violate(RuleClearingSuits, "clearing %d is out of range or given twice", c.GetNumber())
+			}
+			numbers[c.GetNumber()] = true
+			suits[c.GetSuit()]++
+		}
+		if len(clearings) != clearingsPerMap || suits[matchpb.Suit_FOX] != 4 || suits[matchpb.Suit_MOUSE] != 4 || suits[matchpb.Suit_RABBIT] != 4 {
+			violate(RuleClearingSuits, "%d clearings, a map needs 4 of each of fox, mouse and rabbit", len(clearings))
+		}
+	}
+
+	// Matches generated before seats were have none.
+	if seats := match.GetSeats(); len(seats) > 0 {
+		atTable := map[string]bool{}
+		for _, seat := range tableSeats(match) {
+			atTable[seatKey(seat)] = true
+		}
+		seated := map[string]bool{}
+		setup := map[int32]bool{}
+		for _, seat := range seats {
+			if !atTable[seatKey(seat)] || seated[seatKey(seat)] {
+				violate(RuleSeats, "%s isn't at the table or is seated twice", seatKey(seat))
+			}
+			seated[seatKey(seat)] = true
+			if seat.GetSetupOrder() < 1 || int(seat.GetSetupOrder()) > len(seats) || setup[seat.GetSetupOrder()] {
+				violate(RuleSeats, "%s sets up %d, out of range or taken", seatKey(seat), seat.GetSetupOrder())
+			}
+			setup[seat.GetSetupOrder()] = true
+		}
+		if len(seated) != len(atTable) {
+			violate(RuleSeats, "%d seats for %d players and bots", len(seats), len(atTable))
+		}
+		if match.GetFirstSeat() < 1 || int(match.GetFirstSeat()) > len(seats) {
+			violate(RuleSeats, "first seat %d isn't at the table", match.GetFirstSeat())
+		}
+	}
+
+	homes := map[int32]bool{}
+	for _, home := range match.GetHomes() {
+		if !inPlay[home.GetFaction()] {
+			violate(RuleHomes, "%v has a home but isn't in play", home.GetFaction())
+		}
+		if home.GetClearing() < 1 || home.GetClearing() > clearingsPerMap || homes[home.GetClearing()] {
+			violate(RuleHomes, "clearing %d is out of range or home to more than one faction", home.GetClearing())
+		}
+		homes[home.GetClearing()] = true
+	}
+
+	if len(match.GetLandmarks()) > maxLandmarks {
+		violate(RuleTooManyLandmarks, "%d landmarks, at most %d can be used", len(match.GetLandmarks()), maxLandmarks)
+	}
+	landmarks := map[matchpb.LandmarkType]bool{}
+	for _, landmark := range match.GetLandmarks() {
+		if landmarks[landmark.GetType()] {
+			violate(RuleDuplicateLandmark, "%v is placed more than once", landmark.GetType())
+		}
+		landmarks[landmark.GetType()] = true
+		if want := getLandmarkName(int32(landmark.GetType())); landmark.GetName() != want {
+			violate(RuleNameMismatch, "landmark %v is named %q instead of %q", landmark.GetType(), landmark.GetName(), want)
+		}
+	}
+	return violations
+}
+
+func seatKey(seat *matchpb.Seat) string {
+	if seat.GetBot() {
+		return fmt.Sprintf("bot %v", seat.GetFaction())
+	}
+	return fmt.Sprintf("player %v", seat.GetFaction())
+}
+
+type validateResponse struct {
+	Valid      bool        `json:"valid"`
+	Violations []Violation `json:"violations"`
+}
+
+// validateHandler validates a protojson match posted by hand.
+func validateHandler(c echo.Context) error {
+	body, err := io.ReadAll(c.Request().Body)
+	if err != nil {
+		return err
+	}
+	match := &matchpb.Match{}
+	if err := protojson.Unmarshal(body, match); err != nil {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("failed to deserialize match: %v", err))
+	}
+	violations := Validate(match)
+	return c.JSON(http.StatusOK, validateResponse{Valid: len(violations) == 0, Violations: violations})
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"LegacyRoot/matchpb"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func rules(violations []Violation) []string {
+	ids := []string{}
+	for _, v := range violations {
+		ids = append(ids, v.Rule)
+	}
+	return ids
+}
+
+func TestValidateGeneratedMatches(t *testing.T) {
+	prev, err := parseMatch("match.json")
+	require.NoError(t, err)
+	cfg := defaultMatchCfg()
+	cfg.BotEnemies = 2
+	for range 50 {
+		match, _, err := generateMatch(prev, &cfg, newSeed())
+		require.NoError(t, err)
+		assert.Empty(t, Validate(match))
+	}
+}
+
+func TestValidateViolations(t *testing.T) {
+	match := &matchpb.Match{
+		Players: []*matchpb.Faction{{Type: matchpb.FactionType_EYRIE, Name: "Eyrie Dynasties"}},
+		Bots: []*matchpb.Bot{
+			{Type: matchpb.FactionType_EYRIE, Name: "Electric Eyrie", Clockwork: matchpb.BotType_ELECTRIC_EYRIE},
+			{Type: matchpb.FactionType_CORVID, Name: "Corvid Conspiracy"},
+		},
+		Hirelings: []*matchpb.Hireling{
+			{Type: matchpb.FactionType_CORVID, Name: "Corvid Spies", Threshold: 4},
+			{Type: matchpb.FactionType_BAND, Name: "Popular Band", Threshold: 5},
+		},
+		Map: &matchpb.MapVal{Type: matchpb.MapType_LAKE, Name: "Winter"},
+		Landmarks: []*matchpb.Landmark{
+			{Type: matchpb.LandmarkType_TOWER, Name: "The Tower"},
+			{Type: matchpb.LandmarkType_TOWER, Name: "The Tower"},
+			{Type: matchpb.LandmarkType_FERRY, Name: "Lost City"},
+			{Type: matchpb.LandmarkType_MARKET, Name: "Black Market"},
+		},
+	}
+	assert.ElementsMatch(t, []string{
+		RuleDuplicateFaction,
+		RuleHirelingInPlay,
+		RuleHirelingThreshold,
+		RuleMapNameMismatch,
+		RuleTooManyLandmarks,
+		RuleDuplicateLandmark,
+		RuleNameMismatch,
+	}, rules(Validate(match)))
+
+	// A Clockwork name has to come with the matching bot type, matches that
+	// didn't record the type go by the faction.
+	bot := &matchpb.Match{Bots: []*matchpb.Bot{{Type: matchpb.FactionType_EYRIE, Name: "Electric Eyrie", Clockwork: matchpb.BotType_AUTOMATED_ALLIANCE}}}
+	assert.Equal(t, []string{RuleNameMismatch, RuleNameMismatch}, rules(Validate(bot)))
+	bot.Bots[0].Clockwork = matchpb.BotType_ELECTRIC_EYRIE
+	assert.Empty(t, Validate(bot))
+	// An unset Clockwork bot is no longer a Mechanical Marquise.
+	assert.Equal(t, matchpb.BotType_BOT_TYPE_UNSPECIFIED, (&matchpb.Bot{}).GetClockwork())
+	marquise := &matchpb.Bot{Type: matchpb.FactionType_MARQUISE, Name: "Mechanical Marquise 2.0", Clockwork: matchpb.BotType_MECHANICAL_MARQUISE}
+	assert.Empty(t, Validate(&matchpb.Match{Bots: []*matchpb.Bot{marquise}}))
+}
+
+func TestParseMatchRejectsInvalid(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "match.json")
+	err := os.WriteFile(filename, []byte(`{"Players": [{"Type": "MARQUISE", "Name": "Eyrie Dynasties"}]}`), 0o644)
+	require.NoError(t, err)
+	_, err = parseMatch(filename)
+	var violations ValidationError
+	require.ErrorAs(t, err, &violations)
+	assert.Equal(t, []string{RuleNameMismatch}, rules(violations))
+}
+
+func TestValidateEndpoint(t *testing.T) {
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.jsonl"))
+	require.NoError(t, err)
+	e := newServer(history, &Campaigns{})
+
+	body := `{"Players": [{"Type": "CORVID", "Name": "Corvid Conspiracy"}], "Bots": [{"Type": "CORVID", "Name": "Corvid Conspiracy"}]}`
+	req := httptest.NewRequest(http.MethodPost, "/api/matches/validate", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	resp := validateResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	assert.False(t, resp.Valid)
+	assert.Equal(t, []string{RuleDuplicateFaction}, rules(resp.Violations))
+
+	req = httptest.NewRequest(http.MethodPost, "/api/matches/validate", strings.NewReader("{"))
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
@@ -0,0 +1,337 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"LegacyRoot/matchpb"
+
+	"github.com/labstack/echo"
+)
+
+// Clock is the time source of votes, so tests can move time by hand.
+type Clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) Timer
+}
+
+type Timer interface {
+	Stop() bool
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time {
+	return time.Now()
+}
+
+func (realClock) AfterFunc(d time.Duration, f func()) Timer {
+	return time.AfterFunc(d, f)
+}
+
+// VoteRules decide votes on rerolling a component. The table is everyone who
+// joined the session when the vote opened.
+type VoteRules struct {
+	// Majority is the share of the table that has to vote reroll, the vote
+	// passes with more than that.
+	Majority float64
+	// Quorum is how many votes a vote that times out needs to count. It is
+	// then decided by the same majority of the votes cast.
+	Quorum  int
+	Timeout time.Duration
+}
+
+func defaultVoteRules() VoteRules {
+	return VoteRules{Majority: 0.5, Quorum: 1, Timeout: time.Minute}
+}
+
+type VoteOutcome string
+
+const (
+	VoteOpen     VoteOutcome = "open"
+	VoteRerolled VoteOutcome = "rerolled"
+	VoteKept     VoteOutcome = "kept"
+	// VoteExpired is a vote that timed out without a quorum, the component
+	// is kept.
+	VoteExpired VoteOutcome = "expired"
+	// VoteFailed is a vote that passed but whose reroll failed, the
+	// component is kept.
+	VoteFailed VoteOutcome = "failed"
+)
+
+// Vote is a vote on rerolling one component of a session's match.
+type Vote struct {
+	Component matchpb.Component
+	Table     int
+	Deadline  time.Time
+	Outcome   VoteOutcome
+	// Ballots holds each voter's choice, true for reroll.
+	Ballots map[string]bool
+	// Err is why the reroll of a failed vote failed.
+	Err string
+	// voters are the tokens that can vote, those of the table.
+	voters map[string]bool
+	timer  Timer
+}
+
+func (v *Vote) stop() {
+	if v.timer != nil {
+		v.timer.Stop()
+	}
+}
+
+func (v *Vote) count() (keep, reroll int) {
+	for _, r := range v.Ballots {
+		if r {
+			reroll++
+		} else {
+			keep++
+		}
+	}
+	return keep, reroll
+}
+
+// needed is how many reroll votes pass the vote before it times out.
+func (r VoteRules) needed(table int) int {
+	return int(math.Floor(r.Majority*float64(table))) + 1
+}
+
+// decide returns the outcome of the vote so far, or once it timed out.
+func (r VoteRules) decide(v *Vote, expired bool) VoteOutcome {
+	keep, reroll := v.count()
+	needed := r.needed(v.Table)
+	switch {
+	case reroll >= needed:
+		return VoteRerolled
+	case keep > v.Table-needed:
+		return VoteKept
+	case !expired:
+		return VoteOpen
+	case keep+reroll < r.Quorum:
+		return VoteExpired
+	case float64(reroll) > r.Majority*float64(keep+reroll):
+		return VoteRerolled
+	}
+	return VoteKept
+}
+
+// VoteState is what clients see of a vote.
+type VoteState struct {
+	Component string      `json:"component"`
+	Keep      int         `json:"keep"`
+	Reroll    int         `json:"reroll"`
+	Needed    int         `json:"needed"`
+	Deadline  time.Time   `json:"deadline"`
+	Outcome   VoteOutcome `json:"outcome"`
+	Error     string      `json:"error,omitempty"`
+}
+
+// voteStates lists the session's votes by component. s.mu must be held.
+func (s *Session) voteStates() []VoteState {
+	states := []VoteState{}
+	for _, component := range sortedKeys(s.votes) {
+		vote := s.votes[component]
+		keep, reroll := vote.count()
+		states = append(states, VoteState{
+			Component: strings.ToLower(component.String()),
+			Keep:      keep,
+			Reroll:    reroll,
+			Needed:    s.rules.needed(vote.Table),
+			Deadline:  vote.Deadline,
+			Outcome:   vote.Outcome,
+			Error:     vote.Err,
+		})
+	}
+	return states
+}
+
+func (s *Session) Votes() []VoteState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.voteStates()
+}
+
+// OpenVote puts rerolling a component of the session's match to a vote.
+func (s *Session) OpenVote(ctx context.Context, component matchpb.Component) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.record == nil {
+		return echo.NewHTTPError(http.StatusConflict, "the session has no match to vote on yet")
+	}
+	if vote, ok := s.votes[component]; ok && vote.Outcome == VoteOpen {
+		return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("%s are already being voted on", strings.ToLower(component.String())))
+	}
+
+	vote := &Vote{
+		Component: component,
+		Table:     max(len(s.voters), 1),
+		Deadline:  s.clock.Now().Add(s.rules.Timeout),
+		Outcome:   VoteOpen,
+		Ballots:   map[string]bool{},
+		voters:    maps.Clone(s.voters),
+	}
+	vote.timer = s.clock.AfterFunc(s.rules.Timeout, func() {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		if s.votes[component] == vote && vote.Outcome == VoteOpen {
+			// Nobody is waiting on a timed out vote to report errors to.
+			_ = s.resolve(context.Background(), vote, true)
+		}
+	})
+	s.votes[component] = vote
+	return s.broadcast(ctx)
+}
+
+// Cast records a voter's choice on the open vote of a component, changing
+// their earlier choice if they had one. Only voters who joined before the
+// vote opened can vote.
+func (s *Session) Cast(ctx context.Context, component matchpb.Component, voter string, reroll bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	vote, ok := s.votes[component]
+	if !ok || vote.Outcome != VoteOpen {
+		return echo.NewHTTPError(http.StatusConflict, fmt.Sprintf("%s aren't being voted on", strings.ToLower(component.String())))
+	}
+	if !vote.voters[voter] {
+		return echo.NewHTTPError(http.StatusForbidden, "only voters who joined the session before the vote opened can vote")
+	}
+	vote.Ballots[voter] = reroll
+	return s.resolve(ctx, vote, false)
+}
+
+// resolve closes the vote once it is decided, rerolling the component if it
+// passed, and sends the new state to every client. A vote whose reroll fails
+// is closed as failed. s.mu must be held.
+func (s *Session) resolve(ctx context.Context, vote *Vote, expired bool) error {
+	outcome := s.rules.decide(vote, expired)
+	if outcome == VoteOpen {
+		return s.broadcast(ctx)
+	}
+	vote.stop()
+	if outcome == VoteRerolled {
+		if err := s.reroll(vote.Component, s.cfg); err != nil {
+			vote.Outcome, vote.Err = VoteFailed, err.Error()
+			return errors.Join(err, s.broadcast(ctx))
+		}
+		// The reroll calls off the open vote on its component, this one.
+		s.votes[vote.Component] = vote
+	}
+	vote.Outcome = outcome
+	return s.broadcast(ctx)
+}
+
+// formVoteRules reads the vote rules a session is created with, keeping the
+// defaults for those left out.
+func formVoteRules(c echo.Context) (VoteRules, error) {
+	rules := defaultVoteRules()
+	invalid := func(name string, err error) error {
+		return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("invalid %s: %v", name, err))
+	}
+	if s := c.FormValue("vote-majority"); s != "" {
+		majority, err := strconv.ParseFloat(s, 64)
+		if err != nil {
+			return rules, invalid("vote-majority", err)
+		}
+		if majority < 0 || majority >= 1 {
+			return rules, invalid("vote-majority", fmt.Errorf("%v is not in [0, 1)", majority))
+		}
+		rules.Majority = majority
+	}
+	if s := c.FormValue("vote-quorum"); s != "" {
+		quorum, err := strconv.Atoi(s)
+		if err != nil {
+			return rules, invalid("vote-quorum", err)
+		}
+		rules.Quorum = quorum
+	}
+	if s := c.FormValue("vote-timeout"); s != "" {
+		timeout, err := time.ParseDuration(s)
+		if err != nil {
+			return rules, invalid("vote-timeout", err)
+		}
+		if timeout <= 0 {
+			return rules, invalid("vote-timeout", fmt.Errorf("%v is not positive", timeout))
+		}
+		rules.Timeout = timeout
+	}
+	return rules, nil
+}
+
+// voterCookie holds the voter token the session page hands a browser.
+func voterCookie(session *Session) string {
+	return "session-voter-" + session.Id
+}
+
+// voterToken reads who is voting from the X-Voter-Token header, or the cookie
+// the session page sets.
+func voterToken(c echo.Context, session *Session) string {
+	if token := c.Request().Header.Get("X-Voter-Token"); token != "" {
+		return token
+	}
+	if cookie, err := c.Cookie(voterCookie(session)); err == nil {
+		return cookie.Value
+	}
+	return ""
+}
+
+func openVoteHandler(sessions *Sessions) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		session, err := sessionParam(c, sessions)
+		if err != nil {
+			return err
+		}
+		component, err := componentParam(c)
+		if err != nil {
+			return err
+		}
+		if err := session.OpenVote(c.Request().Context(), component); err != nil {
+			return err
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func ballotHandler(sessions *Sessions) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		session, err := sessionParam(c, sessions)
+		if err != nil {
+			return err
+		}
+		component, err := componentParam(c)
+		if err != nil {
+			return err
+		}
+		voter := voterToken(c, session)
+		if !session.IsVoter(voter) {
+			return echo.NewHTTPError(http.StatusForbidden, "join the session to vote")
+		}
+		var reroll bool
+		switch c.FormValue("choice") {
+		case "reroll":
+			reroll = true
+		case "keep":
+		default:
+			return echo.NewHTTPError(http.StatusBadRequest, fmt.Sprintf("choice %q is neither keep nor reroll", c.FormValue("choice")))
+		}
+		if err := session.Cast(c.Request().Context(), component, voter, reroll); err != nil {
+			return err
+		}
+		return c.NoContent(http.StatusNoContent)
+	}
+}
+
+func findVote(votes []VoteState, component string) (VoteState, bool) {
+	for _, vote := range votes {
+		if vote.Component == component {
+			return vote, true
+		}
+	}
+	return VoteState{}, false
+}
@@ -0,0 +1,288 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"LegacyRoot/matchpb"
+
+	"github.com/labstack/echo"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClock only moves when advanced, firing the timers that fall due.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock   *fakeClock
+	at      time.Time
+	f       func()
+	stopped bool
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2024, 1, 1, 20, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) Timer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	timer := &fakeTimer{clock: c, at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, timer)
+	return timer
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	stopped := t.stopped
+	t.stopped = true
+	return !stopped
+}
+
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	c.now = c.now.Add(d)
+	due := []*fakeTimer{}
+	c.timers = slices.DeleteFunc(c.timers, func(t *fakeTimer) bool {
+		if t.stopped || t.at.After(c.now) {
+			return t.stopped
+		}
+		t.stopped = true
+		due = append(due, t)
+		return true
+	})
+	c.mu.Unlock()
+	for _, t := range due {
+		t.f()
+	}
+}
+
+func TestVoteRulesDecide(t *testing.T) {
+	rules := VoteRules{Majority: 0.5, Quorum: 2}
+	ballots := func(keep, reroll int) map[string]bool {
+		b := map[string]bool{}
+		for i := range keep {
+			b["keep"+string(rune('a'+i))] = false
+		}
+		for i := range reroll {
+			b["reroll"+string(rune('a'+i))] = true
+		}
+		return b
+	}
+	for _, tc := range []struct {
+		table, keep, reroll int
+		expired             bool
+		want                VoteOutcome
+	}{
+		{table: 4, reroll: 2, want: VoteOpen},
+		{table: 4, reroll: 3, want: VoteRerolled},
+		{table: 4, keep: 2, want: VoteKept},
+		{table: 3, keep: 1, reroll: 1, want: VoteOpen},
+		{table: 1, reroll: 1, want: VoteRerolled},
+		{table: 4, reroll: 1, expired: true, want: VoteExpired},
+		{table: 4, reroll: 2, expired: true, want: VoteRerolled},
+		{table: 5, keep: 1, reroll: 1, expired: true, want: VoteKept},
+	} {
+		vote := &Vote{Table: tc.table, Ballots: ballots(tc.keep, tc.reroll)}
+		assert.Equal(t, tc.want, rules.decide(vote, tc.expired), "%+v", tc)
+	}
+
+	rules.Majority = 2.0 / 3
+	assert.Equal(t, 3, rules.needed(3))
+	assert.Equal(t, 5, rules.needed(6))
+}
+
+func votingSession(t *testing.T, rules VoteRules, players int) (*Session, *fakeClock, *matchpb.MatchRecord) {
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.pb"))
+	require.NoError(t, err)
+	clock := newFakeClock()
+	session := newSessions(history, clock).Create(rules)
+	match := &matchpb.Match{Map: &matchpb.MapVal{Type: matchpb.MapType_AUTUMN, Name: "Autumn"}}
+	for _, f := range []int32{Marquise, Eyrie, Alliance, Vagabond, Riverfolk}[:players] {
+		match.Players = append(match.Players, NewFaction(f))
+	}
+	record, err := history.Add(match)
+	require.NoError(t, err)
+	cfg := defaultMatchCfg()
+	require.NoError(t, session.Publish(context.Background(), record, &cfg))
+	// Tests vote under names instead of the tokens Join hands out.
+	for _, voter := range []string{"ana", "bo", "cy", "dee", "eli"}[:players] {
+		session.voters[voter] = true
+	}
+	return session, clock, record
+}
+
+func TestSessionVoteMajority(t *testing.T) {
+	ctx := context.Background()
+	session, _, record := votingSession(t, VoteRules{Majority: 0.5, Quorum: 1, Timeout: time.Minute}, 3)
+	events := session.subscribe(0)
+	defer session.unsubscribe(events)
+	<-events
+
+	require.NoError(t, session.OpenVote(ctx, matchpb.Component_LANDMARKS))
+	assert.Error(t, session.OpenVote(ctx, matchpb.Component_LANDMARKS))
+	require.NoError(t, session.Cast(ctx, matchpb.Component_LANDMARKS, "ana", true))
+	// Changing a ballot doesn't count twice.
+	require.NoError(t, session.Cast(ctx, matchpb.Component_LANDMARKS, "ana", true))
+	assert.Equal(t, []VoteState{{
+		Component: "landmarks",
+		Reroll:    1,
+		Needed:    2,
+		Deadline:  time.Date(2024, 1, 1, 20, 1, 0, 0, time.UTC),
+		Outcome:   VoteOpen,
+	}}, session.Votes())
+	assert.Same(t, record, session.Record())
+
+	require.NoError(t, session.Cast(ctx, matchpb.Component_LANDMARKS, "bo", true))
+	assert.Equal(t, VoteRerolled, session.Votes()[0].Outcome)
+	assert.NotSame(t, record, session.Record())
+	assert.Equal(t, record.GetId(), session.Record().GetId())
+	event := <-events
+	assert.Equal(t, VoteRerolled, event.Votes[0].Outcome)
+	assert.Contains(t, event.VotesHTML, "Last vote on landmarks: rerolled.")
+
+	assert.Error(t, session.Cast(ctx, matchpb.Component_LANDMARKS, "cy", false))
+
+	// Enough keeps settle the vote early.
+	require.NoError(t, session.OpenVote(ctx, matchpb.Component_MAP))
+	require.NoError(t, session.Cast(ctx, matchpb.Component_MAP, "ana", false))
+	require.NoError(t, session.Cast(ctx, matchpb.Component_MAP, "bo", false))
+	assert.Equal(t, VoteKept, session.Votes()[0].Outcome)
+}
+
+func TestSessionVoteRerollFails(t *testing.T) {
+	ctx := context.Background()
+	session, _, record := votingSession(t, defaultVoteRules(), 2)
+	events := session.subscribe(0)
+	defer session.unsubscribe(events)
+	<-events
+	// No match can have this many bots.
+	session.cfg.BotEnemies = int32(len(BotCatalog)) + 1
+
+	require.NoError(t, session.OpenVote(ctx, matchpb.Component_BOTS))
+	<-events
+	require.NoError(t, session.Cast(ctx, matchpb.Component_BOTS, "ana", true))
+	<-events
+	assert.Error(t, session.Cast(ctx, matchpb.Component_BOTS, "bo", true))
+	assert.Equal(t, VoteFailed, session.Votes()[0].Outcome)
+	assert.NotEmpty(t, session.Votes()[0].Error)
+	assert.Same(t, record, session.Record())
+	event := <-events
+	assert.Equal(t, VoteFailed, event.Votes[0].Outcome)
+	assert.Contains(t, event.VotesHTML, "Last vote on bots: failed, ")
+
+	// A failed vote is closed, so the table can vote again.
+	require.NoError(t, session.OpenVote(ctx, matchpb.Component_BOTS))
+}
+
+func TestSessionVoteTimeout(t *testing.T) {
+	ctx := context.Background()
+	session, clock, record := votingSession(t, VoteRules{Majority: 0.5, Quorum: 2, Timeout: time.Minute}, 5)
+
+	require.NoError(t, session.OpenVote(ctx, matchpb.Component_MAP))
+	require.NoError(t, session.Cast(ctx, matchpb.Component_MAP, "ana", true))
+	clock.Advance(59 * time.Second)
+	assert.Equal(t, VoteOpen, session.Votes()[0].Outcome)
+	clock.Advance(time.Second)
+	assert.Equal(t, VoteExpired, session.Votes()[0].Outcome)
+	assert.Same(t, record, session.Record())
+
+	require.NoError(t, session.OpenVote(ctx, matchpb.Component_MAP))
+	require.NoError(t, session.Cast(ctx, matchpb.Component_MAP, "ana", true))
+	require.NoError(t, session.Cast(ctx, matchpb.Component_MAP, "bo", true))
+	require.NoError(t, session.Cast(ctx, matchpb.Component_MAP, "cy", false))
+	clock.Advance(time.Minute)
+	assert.Equal(t, VoteRerolled, session.Votes()[0].Outcome)
+	assert.NotSame(t, record, session.Record())
+
+	// A new match calls off open votes and their timers.
+	require.NoError(t, session.OpenVote(ctx, matchpb.Component_BOTS))
+	cfg := defaultMatchCfg()
+	require.NoError(t, session.Publish(ctx, record, &cfg))
+	assert.Empty(t, session.Votes())
+	clock.Advance(time.Minute)
+	assert.Empty(t, session.Votes())
+}
+
+func TestVoteEndpoints(t *testing.T) {
+	history, err := openHistory(filepath.Join(t.TempDir(), "history.pb"))
+	require.NoError(t, err)
+	e := newServer(history, &Campaigns{})
+	postAs := func(target string, form url.Values, header, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(form.Encode()))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationForm)
+		req.Header.Set(header, token)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec
+	}
+	post := func(target string, form url.Values, token string) *httptest.ResponseRecorder {
+		return postAs(target, form, "X-Host-Token", token)
+	}
+	vote := func(component, choice, voter string) int {
+		return postAs("/api/sessions/"+component+"/ballot", url.Values{"choice": {choice}}, "X-Voter-Token", voter).Code
+	}
+
+	rec := post("/api/sessions", url.Values{"vote-majority": {"0.6"}, "vote-timeout": {"30s"}}, "")
+	require.Equal(t, http.StatusCreated, rec.Code)
+	created := createSessionResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	base := "/api/sessions/" + created.Id
+
+	assert.Equal(t, http.StatusBadRequest, post("/api/sessions", url.Values{"vote-majority": {"1.5"}}, "").Code)
+	assert.Equal(t, http.StatusConflict, post(base+"/votes/map", nil, "").Code)
+	require.Equal(t, http.StatusNoContent, post(base+"/generate", url.Values{"players": {"2"}}, created.HostToken).Code)
+
+	// Only the host hands out voter tokens.
+	assert.Equal(t, http.StatusForbidden, post(base+"/voters", nil, "").Code)
+	voters := []string{}
+	for range 3 {
+		rec := post(base+"/voters", nil, created.HostToken)
+		require.Equal(t, http.StatusCreated, rec.Code)
+		joined := joinResponse{}
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &joined))
+		voters = append(voters, joined.VoterToken)
+	}
+
+	require.Equal(t, http.StatusNoContent, post(base+"/votes/map", nil, "").Code)
+	votes := created.Id + "/votes/map"
+	assert.Equal(t, http.StatusBadRequest, vote(votes, "maybe", voters[0]))
+	assert.Equal(t, http.StatusConflict, vote(created.Id+"/votes/bots", "keep", voters[0]))
+
+	// Made up voters can't pass a vote.
+	for _, voter := range []string{"", "a", "b", "c", "d"} {
+		assert.Equal(t, http.StatusForbidden, vote(votes, "reroll", voter))
+	}
+	// Had they counted the vote would be over, the table being the three
+	// voters who joined.
+	assert.Equal(t, http.StatusNoContent, vote(votes, "reroll", voters[0]))
+	assert.Equal(t, http.StatusNoContent, vote(votes, "reroll", voters[0]))
+	rec = post(base+"/voters", nil, created.HostToken)
+	late := joinResponse{}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &late))
+	assert.Equal(t, http.StatusForbidden, vote(votes, "reroll", late.VoterToken))
+	assert.Equal(t, http.StatusNoContent, vote(votes, "reroll", voters[1]))
+	assert.Equal(t, http.StatusConflict, vote(votes, "keep", voters[2]))
+	assert.Equal(t, http.StatusNotFound, post("/api/sessions/missing/votes/map", nil, "").Code)
+}